@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+// benchCommand runs the benchmarks behind the performance claims in
+// lesson comments, and prints each claim's comment with the numbers
+// under it. The benchmarks are in the chapters' _test.go files, so they
+// run with go test, once for each package that has any.
+func benchCommand(args []string) error {
+	fs := newFlagSet("bench", "[claims...]")
+	benchtime := fs.Duration("benchtime", time.Second, "how long to run each benchmark for")
+	patterns, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	claims, err := selectClaims(patterns)
+	if err != nil {
+		return err
+	}
+
+	// Group the benchmarks by package, keeping the claims in order
+	var pkgs []string
+	funcs := map[string][]string{}
+	for _, c := range claims {
+		pkg, err := c.Package()
+		if err != nil {
+			return err
+		}
+		if funcs[pkg] == nil {
+			pkgs = append(pkgs, pkg)
+		}
+		for _, bm := range c.Benchmarks {
+			funcs[pkg] = append(funcs[pkg], bm.Func)
+		}
+	}
+	results := map[string]map[string]benchResult{}
+	for _, pkg := range pkgs {
+		fmt.Fprintf(os.Stderr, "Running the benchmarks in %s...\n", pkg)
+		res, err := goBench(pkg, funcs[pkg], *benchtime)
+		if err != nil {
+			return err
+		}
+		results[pkg] = res
+	}
+
+	for _, c := range claims {
+		fmt.Printf("=== %s: %s\n", c.ID, c.Title)
+		comment, err := c.Source()
+		if err != nil {
+			fmt.Printf("(comment not available: %v)\n\n", err)
+		} else {
+			fmt.Printf("%s:%d\n\n", lessons.RepoPath(comment.File), comment.Line)
+			for _, line := range strings.Split(comment.Text, "\n") {
+				fmt.Printf("  %s\n", strings.TrimSpace(line))
+			}
+			fmt.Println()
+		}
+
+		pkg, _ := c.Package()
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, bm := range c.Benchmarks {
+			res, ok := results[pkg][bm.Func]
+			if !ok {
+				fmt.Fprintf(w, "  %s\tdidn't run (no %s in %s)\n", bm.Name, bm.Func, pkg)
+				continue
+			}
+			if res.failed {
+				fmt.Fprintf(w, "  %s\tfailed\n", bm.Name)
+				continue
+			}
+			fmt.Fprintf(w, "  %s\t%s ns/op\t%s B/op\t%s allocs/op\n", bm.Name, res.nsPerOp, res.bytesPerOp, res.allocsPerOp)
+		}
+		w.Flush()
+		fmt.Println(lessons.Separator)
+	}
+	return nil
+}
+
+// A benchResult is one benchmark's numbers, as go test printed them
+type benchResult struct {
+	nsPerOp, bytesPerOp, allocsPerOp string
+	failed                           bool
+}
+
+// A testEvent is one line of go test -json output
+type testEvent struct {
+	Action     string
+	Test       string
+	Output     string
+	OutputType string
+}
+
+var (
+	nsPerOpRE     = regexp.MustCompile(`([\d.]+) ns/op`)
+	bytesPerOpRE  = regexp.MustCompile(`(\d+) B/op`)
+	allocsPerOpRE = regexp.MustCompile(`(\d+) allocs/op`)
+)
+
+// goBench runs the named benchmarks in pkg with go test, and returns
+// their results by function name
+func goBench(pkg string, funcs []string, benchtime time.Duration) (map[string]benchResult, error) {
+	cmd := exec.Command("go", "test", "-run=^$",
+		"-bench=^("+strings.Join(funcs, "|")+")$",
+		"-benchmem", "-benchtime="+benchtime.String(), "-json", pkg)
+	cmd.Dir = lessons.RepoRoot()
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("running go test: %w", err)
+	}
+
+	// A benchmark's result line can come in more than one output event,
+	// so each benchmark's output is put back together before it's read
+	outputs := map[string]*strings.Builder{}
+	failed := map[string]bool{}
+	var pkgOutput strings.Builder
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		var ev testEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			// Not an event, like a build error printed before the
+			// package's events start
+			pkgOutput.WriteString(scanner.Text() + "\n")
+			continue
+		}
+		switch {
+		case ev.Test == "":
+			if ev.Action == "output" {
+				pkgOutput.WriteString(ev.Output)
+			}
+		case ev.Action == "output" && ev.OutputType != "frame":
+			if outputs[ev.Test] == nil {
+				outputs[ev.Test] = &strings.Builder{}
+			}
+			outputs[ev.Test].WriteString(ev.Output)
+		case ev.Action == "fail":
+			failed[ev.Test] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	results := map[string]benchResult{}
+	for name, out := range outputs {
+		res := benchResult{failed: failed[name]}
+		if m := nsPerOpRE.FindStringSubmatch(out.String()); m != nil {
+			res.nsPerOp = m[1]
+		} else {
+			res.failed = true
+		}
+		if m := bytesPerOpRE.FindStringSubmatch(out.String()); m != nil {
+			res.bytesPerOp = m[1]
+		}
+		if m := allocsPerOpRE.FindStringSubmatch(out.String()); m != nil {
+			res.allocsPerOp = m[1]
+		}
+		results[name] = res
+	}
+	for name := range failed {
+		if _, ok := results[name]; !ok {
+			results[name] = benchResult{failed: true}
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		var exit *exec.ExitError
+		// go test exits 1 when a benchmark fails, which is reported
+		// next to the claim. Anything else, like a build error, means
+		// nothing ran.
+		if !errors.As(err, &exit) || len(results) == 0 {
+			fmt.Fprint(os.Stderr, pkgOutput.String())
+			return nil, fmt.Errorf("go test %s: %w", pkg, err)
+		}
+	}
+	return results, nil
+}
+
+// selectClaims picks claims by ID, glob, or chapter name, the same way
+// lessons are picked
+func selectClaims(patterns []string) ([]lessons.Claim, error) {
+	all := lessons.Claims()
+	if len(patterns) == 0 {
+		return all, nil
+	}
+	var selected []lessons.Claim
+	used := make([]bool, len(patterns))
+	for _, c := range all {
+		chapter, _, _ := strings.Cut(c.ID, "/")
+		for i, p := range patterns {
+			if ok, _ := path.Match(p, c.ID); ok || p == chapter {
+				used[i] = true
+				selected = append(selected, c)
+				break
+			}
+		}
+	}
+	for i, p := range patterns {
+		if !used[i] {
+			return nil, fmt.Errorf("no claims match %q", p)
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,136 @@
+// Package book turns the registered lessons into a book you can read
+// without a terminal: a tree of linked Markdown files, and a static HTML
+// site that doesn't need anything but a browser.
+//
+// Each lesson becomes a section. The comment above the lesson's function
+// is the prose, the function is the code sample, and whatever the lesson
+// printed when the book was built is shown after it.
+package book
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+// A Page is one chapter of the book
+type Page struct {
+	Chapter  lessons.Chapter
+	Sections []Section
+}
+
+// A Section is one lesson in a chapter
+type Section struct {
+	Lesson lessons.Lesson
+	Code   lessons.Code
+	// Prose is the lesson's explanation, and Sample is the code that
+	// goes with it. See split for where each comes from.
+	Prose  []string
+	Sample string
+	// Output is what the lesson printed, and Note is anything the runner
+	// had to say about it, like the panic it recovered from
+	Output string
+	Note   string
+}
+
+// Build runs every lesson in ls and collects what the book needs for
+// each of them. Lessons are grouped into pages by chapter, in the order
+// they come in.
+func Build(ls []lessons.Lesson, opts lessons.Options) ([]Page, error) {
+	var pages []Page
+	for _, l := range ls {
+		if len(pages) == 0 || pages[len(pages)-1].Chapter.Name != l.Chapter {
+			c, _ := lessons.LookupChapter(l.Chapter)
+			pages = append(pages, Page{Chapter: c})
+		}
+
+		code, err := lessons.Source(l)
+		if err != nil {
+			return nil, err
+		}
+		var out bytes.Buffer
+		var res lessons.Result
+		err = lessons.Capture(&out, func() {
+			res = lessons.RunLesson(l, opts)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("capturing %s: %w", l.ID, err)
+		}
+
+		prose, sample := split(code)
+		page := &pages[len(pages)-1]
+		page.Sections = append(page.Sections, Section{
+			Lesson: l,
+			Code:   code,
+			Prose:  prose,
+			Sample: sample,
+			Output: out.String(),
+			Note:   note(res),
+		})
+	}
+	return pages, nil
+}
+
+// note describes how the lesson ended, when that's part of the lesson
+func note(res lessons.Result) string {
+	switch {
+	case res.Status == lessons.TimedOut:
+		return fmt.Sprintf("This lesson was still running after %v, so the runner moved on.", res.Duration.Round(1e6))
+	case res.Panic != nil:
+		return fmt.Sprintf("This lesson panics with: %v", res.Panic)
+	}
+	return ""
+}
+
+// Title turns a chapter's banner title into a heading, so "CHANNELS"
+// becomes "Channels"
+func Title(c lessons.Chapter) string {
+	if c.Title == "" {
+		return c.Name
+	}
+	return c.Title[:1] + strings.ToLower(c.Title[1:])
+}
+
+// anchor is the fragment that links to a lesson's section
+func anchor(l lessons.Lesson) string {
+	return strings.ReplaceAll(l.ID, "/", "-")
+}
+
+// split pulls the prose out of a lesson's code. Some lessons explain
+// themselves in the comment above the function, and most do it in a
+// block of comments at the top of the function body. Both become prose,
+// and the comments at the top of the body are taken out of the sample
+// so they aren't printed twice. Comments further down stay with the
+// lines they're about.
+func split(code lessons.Code) (prose []string, sample string) {
+	prose = paragraphs(code.Doc)
+
+	lines := strings.Split(code.Func, "\n")
+	var intro []string
+	i := 1 // the first line is the func signature
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+		intro = append(intro, strings.TrimSpace(strings.TrimPrefix(line, "//")))
+	}
+	prose = append(prose, paragraphs(strings.Join(intro, "\n"))...)
+	sample = strings.Join(append(lines[:1:1], lines[i:]...), "\n")
+	return prose, sample
+}
+
+// paragraphs splits comment text into the paragraphs it was written as.
+// The line breaks inside a paragraph are kept, since lesson comments are
+// usually a list of short notes more than flowing sentences.
+func paragraphs(text string) []string {
+	var ps []string
+	for _, p := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
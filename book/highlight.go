@@ -0,0 +1,63 @@
+package book
+
+import (
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"html"
+	"html/template"
+	"strings"
+)
+
+// Highlight marks up Go source as HTML, wrapping keywords, literals,
+// comments and predeclared names in spans the stylesheet colors. It
+// uses the same scanner as the compiler, so it never gets confused by
+// something like a "//" inside a string.
+func Highlight(src string) template.HTML {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	// Errors are ignored, since a code sample is still worth showing if
+	// the scanner doesn't understand part of it
+	s.Init(file, []byte(src), nil, scanner.ScanComments)
+
+	var b strings.Builder
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		class := tokenClass(tok, lit)
+		if class == "" {
+			continue
+		}
+		start := file.Offset(pos)
+		end := start + len(lit)
+		if lit == "" {
+			end = start + len(tok.String())
+		}
+		b.WriteString(html.EscapeString(src[last:start]))
+		b.WriteString(`<span class="` + class + `">` + html.EscapeString(src[start:end]) + `</span>`)
+		last = end
+	}
+	b.WriteString(html.EscapeString(src[last:]))
+	return template.HTML(b.String())
+}
+
+func tokenClass(tok token.Token, lit string) string {
+	switch {
+	case tok.IsKeyword():
+		return "kw"
+	case tok == token.COMMENT:
+		return "com"
+	case tok == token.STRING || tok == token.CHAR:
+		return "str"
+	case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+		return "num"
+	case tok == token.IDENT && types.Universe.Lookup(lit) != nil:
+		// int, string, make, len, nil, true and friends
+		return "builtin"
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package book
+
+import (
+	_ "embed"
+	"html/template"
+	"os"
+	"path/filepath"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+// WriteHTML writes the book as a static site into dir: an index.html
+// with the table of contents and a page per chapter. The styles are
+// inlined into every page so the site works straight off the disk.
+func WriteHTML(dir string, pages []Page) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	data := struct {
+		Pages   []Page
+		Current *Page
+		Prev    *Page
+		Next    *Page
+	}{Pages: pages}
+	if err := writeTemplate(filepath.Join(dir, "index.html"), data); err != nil {
+		return err
+	}
+	for i := range pages {
+		data.Current, data.Prev, data.Next = &pages[i], nil, nil
+		if i > 0 {
+			data.Prev = &pages[i-1]
+		}
+		if i+1 < len(pages) {
+			data.Next = &pages[i+1]
+		}
+		if err := writeTemplate(filepath.Join(dir, pages[i].Chapter.Name+".html"), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTemplate(path string, data any) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := pageTemplate.Execute(f, data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Style is the book's stylesheet. The playground uses it too, so the two
+// look the same.
+//
+//go:embed style.css
+var Style string
+
+var pageTemplate = template.Must(template.New("page").Funcs(template.FuncMap{
+	"style":     func() template.CSS { return template.CSS(Style) },
+	"title":     Title,
+	"anchor":    anchor,
+	"highlight": Highlight,
+	"source":    lessons.RepoPath,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{with .Current}}{{title .Chapter}} - {{end}}hellogo</title>
+<style>
+{{style}}</style>
+</head>
+<body>
+<nav>
+<a href="index.html"><strong>hellogo</strong></a>
+<ul>
+{{- range .Pages}}
+<li><a href="{{.Chapter.Name}}.html">{{title .Chapter}}</a>
+<ul>
+{{- $chapter := .Chapter.Name}}
+{{- range .Sections}}
+<li><a href="{{$chapter}}.html#{{anchor .Lesson}}">{{.Lesson.Title}}</a></li>
+{{- end}}
+</ul>
+</li>
+{{- end}}
+</ul>
+</nav>
+<main>
+{{- with .Current}}
+<h1>{{title .Chapter}}</h1>
+{{- range .Sections}}
+<section id="{{anchor .Lesson}}">
+<h2>{{.Lesson.Title}}</h2>
+<p class="where"><code>{{.Lesson.ID}}</code>, from {{source .Code.File}} line {{.Code.Line}}</p>
+{{- range .Prose}}
+<p class="prose">{{.}}</p>
+{{- end}}
+<pre><code>{{highlight .Sample}}</code></pre>
+<p>Output:</p>
+{{- if .Output}}
+<pre class="output">{{.Output}}</pre>
+{{- else}}
+<p><em>This lesson doesn't print anything.</em></p>
+{{- end}}
+{{- with .Note}}
+<p class="note">{{.}}</p>
+{{- end}}
+</section>
+{{- end}}
+{{- else}}
+<h1>hellogo</h1>
+<p>Lessons from learning Go, one chapter at a time. Every code sample is
+the lesson's real source, and every output is what it printed when this
+book was built.</p>
+<h2>Contents</h2>
+<ol>
+{{- range .Pages}}
+<li><a href="{{.Chapter.Name}}.html">{{title .Chapter}}</a> ({{len .Sections}} lessons)</li>
+{{- end}}
+</ol>
+{{- end}}
+<div class="pager">
+<span>{{with .Prev}}<a href="{{.Chapter.Name}}.html">&larr; {{title .Chapter}}</a>{{end}}</span>
+<span>{{with .Next}}<a href="{{.Chapter.Name}}.html">{{title .Chapter}} &rarr;</a>{{end}}</span>
+</div>
+</main>
+</body>
+</html>
+`))
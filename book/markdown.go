@@ -0,0 +1,81 @@
+package book
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+// WriteMarkdown writes the book as Markdown into dir: an index.md with
+// the table of contents and a page per chapter, each linking to the ones
+// around it.
+func WriteMarkdown(dir string, pages []Page) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	var index strings.Builder
+	index.WriteString("# hellogo\n\n## Contents\n\n")
+	for _, p := range pages {
+		fmt.Fprintf(&index, "- [%s](%s.md)\n", Title(p.Chapter), p.Chapter.Name)
+		for _, s := range p.Sections {
+			fmt.Fprintf(&index, "  - [%s](%s.md#%s)\n", s.Lesson.Title, p.Chapter.Name, anchor(s.Lesson))
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.md"), []byte(index.String()), 0o644); err != nil {
+		return err
+	}
+
+	for i, p := range pages {
+		var b strings.Builder
+		fmt.Fprintf(&b, "# %s\n\n", Title(p.Chapter))
+		b.WriteString(markdownNav(pages, i))
+		for _, s := range p.Sections {
+			// An explicit anchor, since every Markdown renderer makes
+			// up its own from the heading text
+			fmt.Fprintf(&b, "<a id=\"%s\"></a>\n\n## %s\n\n", anchor(s.Lesson), s.Lesson.Title)
+			fmt.Fprintf(&b, "_`%s`, from %s line %d_\n\n", s.Lesson.ID, lessons.RepoPath(s.Code.File), s.Code.Line)
+			for _, para := range s.Prose {
+				// Two spaces at the end of a line keep the line break
+				b.WriteString(strings.ReplaceAll(para, "\n", "  \n") + "\n\n")
+			}
+			fmt.Fprintf(&b, "```go\n%s\n```\n\n", s.Sample)
+			b.WriteString("Output:\n\n")
+			if s.Output == "" {
+				b.WriteString("_This lesson doesn't print anything._\n\n")
+			} else {
+				// The closing fence has to start a line of its own, or it
+				// ends up in the output
+				out := s.Output
+				if !strings.HasSuffix(out, "\n") {
+					out += "\n"
+				}
+				fmt.Fprintf(&b, "```text\n%s```\n\n", out)
+			}
+			if s.Note != "" {
+				fmt.Fprintf(&b, "> %s\n\n", s.Note)
+			}
+		}
+		b.WriteString(markdownNav(pages, i))
+		if err := os.WriteFile(filepath.Join(dir, p.Chapter.Name+".md"), []byte(b.String()), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func markdownNav(pages []Page, i int) string {
+	links := []string{"[Contents](index.md)"}
+	if i > 0 {
+		prev := pages[i-1].Chapter
+		links = append([]string{fmt.Sprintf("[← %s](%s.md)", Title(prev), prev.Name)}, links...)
+	}
+	if i+1 < len(pages) {
+		next := pages[i+1].Chapter
+		links = append(links, fmt.Sprintf("[%s →](%s.md)", Title(next), next.Name))
+	}
+	return strings.Join(links, " | ") + "\n\n"
+}
@@ -0,0 +1,42 @@
+package book
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+func TestMarkdownOutputFence(t *testing.T) {
+	chapter := lessons.Chapter{Name: "printing", Title: "Printing"}
+	page := Page{Chapter: chapter}
+	for id, out := range map[string]string{
+		"println": "with a newline\n",
+		"print":   "without a newline",
+	} {
+		page.Sections = append(page.Sections, Section{
+			Lesson: lessons.Lesson{ID: "printing/" + id, Chapter: chapter.Name, Title: id},
+			Sample: `fmt.Print("...")`,
+			Output: out,
+		})
+	}
+	dir := t.TempDir()
+	if err := WriteMarkdown(dir, []Page{page}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "printing.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := string(b)
+	for _, want := range []string{
+		"```text\nwith a newline\n```\n",
+		"```text\nwithout a newline\n```\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("page doesn't contain %q:\n%s", want, md)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package channels
+
+import (
+	"fmt"
+
+	"github.com/nicolasjhampton/hellogo/pubsub"
+)
+
+func channelBroker() {
+	// The pubsub package hands each subscriber a buffered channel of its
+	// own, and copies every message published to a topic onto the
+	// channel of everyone subscribed to it
+	b := pubsub.New[string](pubsub.DefaultOptions)
+	news := b.Subscribe("news")
+	alsoNews := b.Subscribe("news")
+	weather := b.Subscribe("weather")
+
+	b.Publish("news", "Gophers everywhere")
+	b.Publish("weather", "Sunny")
+	// Once a subscriber unsubscribes, its channel is closed and nothing
+	// more is sent to it. What was already waiting can still be read.
+	alsoNews.Unsubscribe()
+	n, _ := b.Publish("news", "Channels still work")
+	fmt.Println("second story went to", n, "subscriber")
+
+	// Closing the broker closes every channel, so ranging over one ends
+	// instead of deadlocking, just like in channelRange
+	b.Close()
+	for _, sub := range []*pubsub.Subscription[string]{news, alsoNews, weather} {
+		for msg := range sub.C {
+			fmt.Println(sub.Topic, "got:", msg)
+		}
+	}
+	_, err := b.Publish("news", "Too late")
+	fmt.Println(err)
+
+	// A subscriber that never reads fills up its buffer. With
+	// DropOldest, the oldest messages make way for new ones instead of
+	// the publisher waiting on it forever.
+	small := pubsub.New[int](pubsub.Options{BufferSize: 2, SlowSubscriber: pubsub.DropOldest})
+	stuck := small.Subscribe("numbers")
+	for i := 1; i <= 5; i++ {
+		small.Publish("numbers", i)
+	}
+	small.Close()
+	for i := range stuck.C {
+		fmt.Println(i)
+	}
+	fmt.Println("dropped", stuck.Dropped())
+}
@@ -4,24 +4,32 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
 )
 
-var channelLessons = []func(){
-	channelBasics,
-	channelForAsync,
-	channelRestrictions,
-	channelBuffered,
-	channelRange,
-	channelCloseCheck,
-	channelSelect,
+var channelLessons = []lessons.Lesson{
+	{ID: "channels/basics", Title: "Channel basics", Run: channelBasics, Teaches: []string{"channels"}, Requires: []string{"goroutines", "waitgroups"}, Unordered: true},
+	{ID: "channels/for-async", Title: "Many goroutines, one channel", Run: channelForAsync, Requires: []string{"channels"}, Unordered: true},
+	{ID: "channels/restrictions", Title: "Send-only and receive-only channels", Run: channelRestrictions, Teaches: []string{"channel directions"}, Requires: []string{"channels"}},
+	{ID: "channels/buffered", Title: "Buffered channels", Run: channelBuffered, Requires: []string{"channel directions"}, Quiz: &lessons.Quiz{
+		Distractors: []string{"42\n27", "27"},
+	}},
+	{ID: "channels/range", Title: "Ranging over a channel", Run: channelRange, Teaches: []string{"closing channels"}, Requires: []string{"channels/buffered"}},
+	{ID: "channels/close-check", Title: "Checking for a closed channel", Run: channelCloseCheck, Requires: []string{"closing channels"}},
+	{ID: "channels/select", Title: "Select and the logger", Run: channelSelect, Teaches: []string{"select"}, Requires: []string{"closing channels", "defer"}},
+	{ID: "channels/broker", Title: "Publishing and subscribing", Run: channelBroker, Requires: []string{"closing channels"}, Quiz: &lessons.Quiz{
+		Distractors: []string{"1\n2\ndropped 3", "4\n5\ndropped 0"},
+		Tail:        3,
+	}},
+}
+
+func init() {
+	lessons.Register(lessons.Chapter{Name: "channels", Title: "CHANNELS", Order: 100}, channelLessons...)
 }
 
 func ChannelLessons() {
-	fmt.Println("////////////////////////////*CHANNELS*////////////////////////////")
-	for _, lesson := range channelLessons {
-		lesson()
-		fmt.Println("------------------------------------------------------------------")
-	}
+	lessons.RunChapter("channels")
 }
 
 // Need a waitgroup for flow control of the outer scope
@@ -194,6 +202,10 @@ func logger() (chan logEntry, chan struct{}) {
 			case entry := <- logCh:
 				fmt.Printf("%v - [%v] %v\n", entry.time.Format("2006-01-02T15:04:05"), entry.severity, entry.message)
 			case <- doneCh:
+				// Careful, this break only leaves the select, so the
+				// loop keeps going and this goroutine never ends. The
+				// logger package fixes that, and writes out what's
+				// left in logCh before it stops.
 				break
 			}
 		}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+// A command is one of the hellogo subcommands. It gets the arguments
+// that come after its name.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands []command
+
+func init() {
+	// commands is filled in here instead of in its declaration so the
+	// help command can refer back to it
+	commands = []command{
+		{"list", "print the chapters and their lessons", listCommand},
+		{"run", "run lessons by ID, glob, or chapter", runCommand},
+		{"step", "walk through lessons one at a time", stepCommand},
+		{"quiz", "predict what lessons print, then check", quizCommand},
+		{"path", "show which lessons to do first, and in what order", pathCommand},
+		{"progress", "show how much of each chapter you've done", progressCommand},
+		{"continue", "run from the first lesson you haven't finished", continueCommand},
+		{"explore", "run lessons many times and count the different outputs", exploreCommand},
+		{"race", "run lessons under the race detector and show where they race", raceCommand},
+		{"bench", "benchmark the performance claims in lesson comments", benchCommand},
+		{"exercise", "list, start and check the chapter exercises", exerciseCommand},
+		{"serve", "browse and run lessons in a web browser", serveCommand},
+		{"verify", "check lesson output against the golden files", verifyCommand},
+		{"export", "write the lessons out as a Markdown and HTML book", exportCommand},
+		{"help", "show this message", helpCommand},
+	}
+}
+
+// errUsage is returned when the arguments don't make sense. The usage
+// message has already been printed, so main only needs to exit.
+var errUsage = errors.New("usage")
+
+func runCLI(args []string) error {
+	if len(args) == 0 {
+		// No subcommand runs the whole book, the way hellogo always has
+		return runCommand(nil)
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:])
+		}
+	}
+	fmt.Fprintf(os.Stderr, "hellogo: unknown command %q\n\n", args[0])
+	usage()
+	return errUsage
+}
+
+func usage() {
+	w := tabwriter.NewWriter(os.Stderr, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "Usage: hellogo <command> [flags] [lessons...]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %s\t%s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Lessons are picked by ID (channels/select), glob (interfaces/*),")
+	fmt.Fprintln(w, "or chapter name (pointers). Run \"hellogo <command> -h\" for flags.")
+	w.Flush()
+}
+
+func helpCommand(args []string) error {
+	usage()
+	return nil
+}
+
+// listFlag is a flag that can be repeated or given a comma separated
+// list, so --skip a --skip b and --skip a,b mean the same thing
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(v string) error {
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			*l = append(*l, s)
+		}
+	}
+	return nil
+}
+
+// filterFlags adds the flags every lesson-picking command shares and
+// returns the filter they fill in
+func filterFlags(fs *flag.FlagSet) *lessons.Filter {
+	f := &lessons.Filter{}
+	fs.Var((*listFlag)(&f.Chapters), "chapter", "only pick lessons from these chapters")
+	fs.Var((*listFlag)(&f.Skip), "skip", "leave out lessons matching these IDs or globs")
+	fs.StringVar(&f.From, "from", "", "start at this lesson ID")
+	return f
+}
+
+// runnerFlags adds the flags that control how lessons are run and
+// returns the options they fill in
+func runnerFlags(fs *flag.FlagSet) *lessons.Options {
+	opts := lessons.DefaultOptions
+	fs.DurationVar(&opts.Timeout, "timeout", opts.Timeout, "how long each lesson gets to run, 0 for no limit")
+	fs.BoolVar(&opts.Strict, "strict", opts.Strict, "fail lessons that leave goroutines running")
+	fs.BoolVar(&opts.ShowSource, "show-source", opts.ShowSource, "print each lesson's code and comments before running it")
+	fs.DurationVar(&opts.LeakGrace, "leak-grace", opts.LeakGrace, "how long a lesson's goroutines get to finish before they count as leaked")
+	return &opts
+}
+
+// formatFlag adds the flag for how a run is reported
+func formatFlag(fs *flag.FlagSet) *string {
+	return fs.String("format", "text", "how to report the run: text, or json for a stream of events, one per line")
+}
+
+// runFormatted runs ls and reports on it in format
+func runFormatted(ls []lessons.Lesson, opts lessons.Options, format string) ([]lessons.Result, error) {
+	switch format {
+	case "text":
+		return lessons.Run(ls, opts), nil
+	case "json":
+		// Lesson output is captured into events, but goroutines a
+		// lesson leaves behind can still print after it's over. Pointing
+		// stdout at stderr for the run keeps those lines out of the
+		// event stream.
+		stdout := os.Stdout
+		os.Stdout = os.Stderr
+		defer func() { os.Stdout = stdout }()
+		return lessons.RunWith(ls, opts, lessons.JSONReporter(stdout)), nil
+	}
+	return nil, fmt.Errorf("unknown format %q, want text or json", format)
+}
+
+// parseArgs parses flags wherever they show up, so both
+// "run --skip x channels/*" and "run channels/* --skip x" work. It
+// returns the arguments that weren't flags.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var rest []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			// The flag package already printed what went wrong
+			return nil, errUsage
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return rest, nil
+		}
+		rest = append(rest, args[0])
+		args = args[1:]
+	}
+}
+
+// selectLessons parses args for a command and returns the lessons the
+// command should work on
+func selectLessons(fs *flag.FlagSet, f *lessons.Filter, args []string) ([]lessons.Lesson, error) {
+	patterns, err := parseArgs(fs, args)
+	if err != nil {
+		return nil, err
+	}
+	f.Patterns = patterns
+	return f.Select(lessons.All())
+}
+
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: hellogo %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func listCommand(args []string) error {
+	fs := newFlagSet("list", "[lessons...]")
+	f := filterFlags(fs)
+	ls, err := selectLessons(fs, f, args)
+	if err != nil {
+		return err
+	}
+
+	byChapter := map[string][]lessons.Lesson{}
+	for _, l := range ls {
+		byChapter[l.Chapter] = append(byChapter[l.Chapter], l)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, c := range lessons.Chapters() {
+		chapterLessons := byChapter[c.Name]
+		if len(chapterLessons) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s (%s)\n", c.Title, c.Name)
+		for _, l := range chapterLessons {
+			fmt.Fprintf(w, "  %s\t%s\n", l.ID, l.Title)
+		}
+	}
+	return w.Flush()
+}
+
+func runCommand(args []string) error {
+	fs := newFlagSet("run", "[lessons...]")
+	f := filterFlags(fs)
+	opts := runnerFlags(fs)
+	o := learnerFlags(fs)
+	raw := fs.Bool("raw", false, "print only what the lessons print, with no banners or reports, and don't record progress")
+	format := formatFlag(fs)
+	ls, err := selectLessons(fs, f, args)
+	if err != nil {
+		return err
+	}
+	if *raw {
+		var results []lessons.Result
+		for _, l := range ls {
+			results = append(results, lessons.RunLesson(l, *opts))
+		}
+		return lessons.Summarize(results)
+	}
+	warnAhead(os.Stderr, ls, o)
+	results, err := runFormatted(ls, *opts, *format)
+	if err != nil {
+		return err
+	}
+	// Not being able to save progress, like with a read-only config
+	// directory, shouldn't fail the lessons themselves
+	if err := o.record(results...); err != nil {
+		fmt.Fprintln(os.Stderr, "hellogo:", err)
+	}
+	return lessons.Summarize(results)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+	"github.com/nicolasjhampton/hellogo/progress"
+)
+
+// captureStdout runs f with os.Stdout going to a pipe, and returns
+// everything written to it
+func captureStdout(t *testing.T, f func() error) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+	err = f()
+	w.Close()
+	return <-out, err
+}
+
+func TestContinueJSON(t *testing.T) {
+	// Everything but the last lesson is done, so continue runs just that
+	path := filepath.Join(t.TempDir(), "progress.json")
+	state, err := progress.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := lessons.All()
+	learner := state.Learner("test")
+	for _, l := range all[:len(all)-1] {
+		learner.Record(lessons.Result{Lesson: l, Status: lessons.Passed}, time.Now())
+	}
+	if err := state.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := captureStdout(t, func() error {
+		return continueCommand([]string{"--format=json", "--progress-file", path, "--user", "test"})
+	})
+	if err != nil {
+		t.Fatalf("continue: %v", err)
+	}
+	// Fields that go with an event are there even when they're zero,
+	// like no leaks, or no failures at the end of the run
+	fields := map[string][]string{
+		"lesson-end": {"status", "duration_ms", "leaks"},
+		"run-end":    {"passed", "failed", "total"},
+	}
+	kinds := map[string]bool{}
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		var ev map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Errorf("stdout line %q isn't a JSON event: %v", scanner.Text(), err)
+			continue
+		}
+		kind, _ := ev["event"].(string)
+		kinds[kind] = true
+		for _, f := range fields[kind] {
+			if _, ok := ev[f]; !ok {
+				t.Errorf("%s event has no %s: %s", kind, f, scanner.Text())
+			}
+		}
+	}
+	for _, k := range []string{"run-start", "lesson-end", "run-end"} {
+		if !kinds[k] {
+			t.Errorf("no %s event in %q", k, out)
+		}
+	}
+}
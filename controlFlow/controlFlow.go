@@ -0,0 +1,238 @@
+package controlFlow
+
+import (
+	"fmt"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+var controlFlowLessons = []lessons.Lesson{
+	{ID: "controlFlow/for-loops", Title: "The for loop", Run: controlFlowForLoops, Teaches: []string{"for loops"}},
+	{ID: "controlFlow/while", Title: "for as a while loop", Run: controlFlowWhile, Requires: []string{"for loops"}},
+	{ID: "controlFlow/continue", Title: "Skipping ahead with continue", Run: controlFlowContinue, Teaches: []string{"break and continue"}, Requires: []string{"controlFlow/while"}},
+	{ID: "controlFlow/labels", Title: "Labeled break and continue", Run: controlFlowLabels, Teaches: []string{"labels"}, Requires: []string{"break and continue"}, Quiz: &lessons.Quiz{
+		Distractors: []string{"0 0\n0 1\n0 2\n1 0\n1 1\n1 2\n2 0\n2 1\nfound 2 1", "0 0\n1 0\n2 0\n2 1\nfound 2 1"},
+	}},
+	{ID: "controlFlow/range", Title: "Looping over collections with range", Run: controlFlowRange, Teaches: []string{"range"}, Requires: []string{"for loops"}},
+	{ID: "controlFlow/if-init", Title: "if with an initializer", Run: controlFlowIfInit, Teaches: []string{"if"}},
+	{ID: "controlFlow/switch", Title: "switch and fallthrough", Run: controlFlowSwitch, Teaches: []string{"switch"}, Requires: []string{"if"}, Quiz: &lessons.Quiz{
+		Question:    "The last lines come from the switch with fallthrough. What does it print?",
+		Tail:        2,
+		Distractors: []string{"less than or equal to ten", "less than or equal to ten\nyou'll never get here"},
+	}},
+	{ID: "controlFlow/goto", Title: "goto", Run: controlFlowGoto, Requires: []string{"labels"}},
+}
+
+func init() {
+	lessons.Register(lessons.Chapter{Name: "controlFlow", Title: "CONTROL FLOW", Order: 20}, controlFlowLessons...)
+}
+
+func ControlFlowLessons() {
+	lessons.RunChapter("controlFlow")
+}
+
+func controlFlowForLoops() {
+	// The classic form: an initializer, a condition and an
+	// incrementer, split up by semicolons. No parentheses needed.
+	for i := 0; i < 5; i++ {
+		fmt.Println(i)
+	}
+
+	// Go doesn't have a comma operator, so to work with two variables
+	// we initialize and increment them both at once with tuple style
+	// assignments. i++ is a statement, not an expression, which is why
+	// this isn't i++, j += 2
+	for i, j := 0, 0; i < 5; i, j = i+1, j+2 {
+		fmt.Println(i, j)
+	}
+
+	// The initializer is optional. If i is declared outside the loop,
+	// it's still around after the loop is done, instead of being
+	// scoped to the for statement
+	i := 0
+	for ; i < 5; i++ {
+		fmt.Println(i)
+	}
+	fmt.Println("after the loop, i is", i)
+}
+
+func controlFlowWhile() {
+	// Go doesn't have a while keyword. A for loop with only a
+	// condition does the same job
+	i := 0
+	for i < 5 {
+		fmt.Println(i)
+		i++
+	}
+
+	// Leave the condition off too and the loop runs forever, so
+	// something inside has to break out of it. This is a do while
+	// loop, since the body always runs at least once.
+	i = 0
+	for {
+		fmt.Println(i)
+		i++
+		if i == 5 {
+			break // leaves the loop right away
+		}
+	}
+}
+
+func controlFlowContinue() {
+	// continue skips the rest of the body and goes on to the next
+	// time around the loop, so only the odd numbers print
+	for i := 0; i < 10; i++ {
+		if i%2 == 0 {
+			continue
+		}
+		fmt.Println(i)
+	}
+}
+
+func controlFlowLabels() {
+	// break and continue only work on the innermost loop they're in.
+	// To get at an outer loop, we put a label in front of it and name
+	// the label after break or continue
+Outer:
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if j == 2 {
+				// goes straight on to the next i, instead of
+				// only ending this inner loop
+				continue Outer
+			}
+			fmt.Println(i, j)
+			if i*j == 2 {
+				// stops both loops at once. Without the label, this
+				// would only end the inner loop and the outer one
+				// would keep going
+				fmt.Println("found", i, j)
+				break Outer
+			}
+		}
+	}
+	// Labels have to be used, just like variables, or the compiler
+	// complains
+}
+
+func controlFlowRange() {
+	// range gives us the index and the value of each element
+	s := []string{"top", "middle", "bottom"}
+	for k, v := range s {
+		fmt.Printf("%v: %v", k, v)
+		fmt.Println("")
+	}
+
+	// If we only need the values, the index goes to the blank
+	// identifier, since unused variables don't compile
+	for _, v := range s {
+		fmt.Println(v)
+	}
+
+	// Ranging over a string walks through its runes, not its bytes.
+	// The index is the byte the rune starts at, so it can jump by more
+	// than one
+	for i, r := range "héllo" {
+		fmt.Println(i, string(r))
+	}
+
+	// Since Go 1.22 we can range over an integer too, which counts
+	// from 0 up to one less than it
+	for i := range 3 {
+		fmt.Println(i)
+	}
+}
+
+func controlFlowIfInit() {
+	// Conditions don't need parentheses, but the braces are required,
+	// even for a one line body
+	number := 50
+	if number < 100 {
+		fmt.Println("less than 100")
+	}
+
+	// An if can start with a statement, separated from the condition
+	// with a semicolon. Variables declared there only exist inside the
+	// if and its else blocks, which keeps them out of the way. Looking
+	// a key up in a map gives back a second value saying whether it was
+	// there, and the maps chapter has more on that
+	bird := map[string]string{
+		"name":   "Emu",
+		"origin": "Australia",
+	}
+	if pop, ok := bird["origin"]; ok {
+		fmt.Println(pop)
+	}
+	// fmt.Println(pop) wouldn't compile out here
+
+	// This is really common for errors
+	if n, err := fmt.Sscan("42", &number); err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Println("scanned", n, "value:", number)
+	}
+}
+
+func controlFlowSwitch() {
+	// Cases can list more than one value, and unlike C, they don't
+	// fall through to the next case. A break at the end of each case
+	// is implied.
+	switch i := 2 + 3; i {
+	case 1, 5, 10:
+		fmt.Println("one, five, or ten")
+	case 2, 4, 6:
+		fmt.Println("two, four, or six")
+	default:
+		fmt.Println("another number")
+	}
+
+	// A switch with no tag is a cleaner way to write a chain of if
+	// else. Each case is its own condition and the first true one
+	// wins, even if later ones are true too
+	i := 10
+	switch {
+	case i <= 10:
+		fmt.Println("less than or equal to ten")
+	case i <= 20:
+		fmt.Println("less than or equal to twenty")
+	default:
+		fmt.Println("greater than twenty")
+	}
+
+	// fallthrough runs the next case too. It doesn't check the next
+	// case's condition, it just runs its body, so it's easy to get
+	// wrong and doesn't turn up much
+	switch {
+	case i <= 10:
+		fmt.Println("less than or equal to ten")
+		fallthrough
+	case i > 20:
+		fmt.Println("greater than twenty, or so says fallthrough")
+	default:
+		fmt.Println("you'll never get here")
+	}
+}
+
+func controlFlowGoto() {
+	// goto jumps to a label in the same function. It can't jump over
+	// a variable declaration or into a block, which keeps it from
+	// getting too wild, but loops are almost always clearer
+	i := 0
+Loop:
+	if i < 3 {
+		fmt.Println(i)
+		i++
+		goto Loop
+	}
+
+	// The one place it still shows up is jumping to shared cleanup
+	// code at the end of a function
+	if err := fmt.Errorf("something went wrong"); err != nil {
+		goto Fail
+	}
+	fmt.Println("all good")
+	return
+
+Fail:
+	fmt.Println("cleaning up after the error")
+}
@@ -5,20 +5,26 @@ import (
 	"io"
 	"log"
 	"net/http"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
 )
 
-var deferLessons = []func(){
-	deferOrder,
-	// deferServer,
-	deferVariables,
+var deferLessons = []lessons.Lesson{
+	{ID: "defer/order", Title: "Deferred calls run last", Run: deferOrder, Teaches: []string{"defer"}, Quiz: &lessons.Quiz{
+		Distractors: []string{"start\nmiddle\nend", "middle\nstart\nend"},
+	}},
+	// {ID: "defer/server", Title: "Closing resources with defer", Run: deferServer},
+	{ID: "defer/variables", Title: "Deferred arguments are evaluated early", Run: deferVariables, Requires: []string{"defer/order"}, Quiz: &lessons.Quiz{
+		Distractors: []string{"end", "start\nend"},
+	}},
+}
+
+func init() {
+	lessons.Register(lessons.Chapter{Name: "defer", Title: "DEFER", Order: 30}, deferLessons...)
 }
 
 func DeferLessons() {
-	fmt.Println("////////////////////////////*DEFER*////////////////////////////")
-	for _, lesson := range deferLessons {
-		lesson()
-		fmt.Println("------------------------------------------------------------------")
-	}
+	lessons.RunChapter("defer")
 }
 
 func deferOrder() {
@@ -3,20 +3,28 @@ package deferPanicRecover
 import (
 	"fmt"
 	"net/http"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
 )
 
-var panicLessons = []func(){
-	// panicDivision,
-	// panicWebHandler,
-	// panicWithDefer,
+var panicLessons = []lessons.Lesson{
+	// The runner recovers from each lesson's panic, so these can run
+	// without taking the rest of the book down with them
+	{ID: "panic/division", Title: "A runtime panic", Run: panicDivision, Teaches: []string{"panic"}, Panics: "integer divide by zero"},
+	// panicWebHandler only panics if :8080 is already taken. Otherwise
+	// it serves forever, so it stays out of the book
+	// {ID: "panic/web-handler", Title: "Choosing to panic on an error", Run: panicWebHandler},
+	{ID: "panic/with-defer", Title: "Defers run before a panic", Run: panicWithDefer, Requires: []string{"panic", "defer"}, Panics: "something bad happened", Quiz: &lessons.Quiz{
+		Distractors: []string{"start", "start\nend\nthis was deferred"},
+	}},
+}
+
+func init() {
+	lessons.Register(lessons.Chapter{Name: "panic", Title: "PANIC", Order: 40}, panicLessons...)
 }
 
 func PanicLessons() {
-	fmt.Println("////////////////////////////*PANIC*////////////////////////////")
-	for _, lesson := range panicLessons {
-		lesson()
-		fmt.Println("------------------------------------------------------------------")
-	}
+	lessons.RunChapter("panic")
 }
 
 func panicDivision() {
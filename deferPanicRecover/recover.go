@@ -3,19 +3,21 @@ package deferPanicRecover
 import (
 	"fmt"
 	"log"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
 )
 
-var recoverLessons = []func(){
-	recoverUse,
-	recoverPanicker,
+var recoverLessons = []lessons.Lesson{
+	{ID: "recover/use", Title: "Catching a panic with recover", Run: recoverUse, Teaches: []string{"recover"}, Requires: []string{"panic/with-defer"}},
+	{ID: "recover/panicker", Title: "Continuing after a recovered panic", Run: recoverPanicker, Requires: []string{"recover"}},
+}
+
+func init() {
+	lessons.Register(lessons.Chapter{Name: "recover", Title: "RECOVER", Order: 50}, recoverLessons...)
 }
 
 func RecoverLessons() {
-	fmt.Println("////////////////////////////*RECOVER*////////////////////////////")
-	for _, lesson := range recoverLessons {
-		lesson()
-		fmt.Println("------------------------------------------------------------------")
-	}
+	lessons.RunChapter("recover")
 }
 
 func recoverUse() {
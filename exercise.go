@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nicolasjhampton/hellogo/exercises"
+)
+
+// currentFile remembers the exercise most recently started in a
+// workspace, so exercise check doesn't need to be told which one
+const currentFile = ".current"
+
+// exerciseCommand handles the exercise subcommands: list, start and check
+func exerciseCommand(args []string) error {
+	fs := newFlagSet("exercise", "list | start <id> | check [id]")
+	workspace := fs.String("dir", "hellogo-workspace", "workspace directory exercises are copied into")
+	force := fs.Bool("force", false, "start over, replacing the file in the workspace")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	switch rest[0] {
+	case "list":
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, e := range exercises.All() {
+			fmt.Fprintf(w, "%s\t%s\t(from %s)\n", e.ID, e.Title, e.Lesson)
+		}
+		return w.Flush()
+
+	case "start":
+		if len(rest) != 2 {
+			return fmt.Errorf("exercise start needs an exercise ID, see exercise list")
+		}
+		e, err := lookupExercise(rest[1])
+		if err != nil {
+			return err
+		}
+		file, err := exercises.Start(*workspace, e, *force)
+		if errors.Is(err, exercises.ErrStarted) {
+			return fmt.Errorf("%s is already in %s, use --force to start over", e.ID, file)
+		}
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(*workspace, currentFile), []byte(e.ID+"\n"), 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("Started %s: %s\n", e.ID, e.Title)
+		fmt.Printf("Fill in the TODOs in %s, then run: hellogo exercise check\n", file)
+		return nil
+
+	case "check":
+		id := ""
+		if len(rest) > 1 {
+			id = rest[1]
+		} else {
+			data, err := os.ReadFile(filepath.Join(*workspace, currentFile))
+			if err != nil {
+				return fmt.Errorf("no exercise started in %s, run exercise start <id> first", *workspace)
+			}
+			id = strings.TrimSpace(string(data))
+		}
+		e, err := lookupExercise(id)
+		if err != nil {
+			return err
+		}
+		report, err := exercises.Check(*workspace, e)
+		if err != nil {
+			return err
+		}
+		return printReport(e, report)
+	}
+	return fmt.Errorf("unknown exercise command %q, want list, start or check", rest[0])
+}
+
+func lookupExercise(id string) (exercises.Exercise, error) {
+	e, ok := exercises.Lookup(id)
+	if !ok {
+		return e, fmt.Errorf("no exercise %q, see exercise list", id)
+	}
+	return e, nil
+}
+
+func printReport(e exercises.Exercise, report exercises.Report) error {
+	if report.BuildOutput != "" {
+		fmt.Printf("%s doesn't build yet:\n\n%s\n", e.ID, report.BuildOutput)
+		return fmt.Errorf("%s didn't build", e.ID)
+	}
+	failed := 0
+	for _, c := range report.Checks {
+		if c.Passed {
+			fmt.Printf("  pass  %s\n", c.Name)
+			continue
+		}
+		failed++
+		fmt.Printf("  FAIL  %s\n", c.Name)
+		for _, line := range strings.Split(strings.TrimSpace(c.Output), "\n") {
+			fmt.Printf("        %s\n", strings.TrimSpace(line))
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed for %s", failed, len(report.Checks), e.ID)
+	}
+	fmt.Printf("All %d checks pass. %s is done!\n", len(report.Checks), e.ID)
+	return nil
+}
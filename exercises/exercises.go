@@ -0,0 +1,235 @@
+// Package exercises holds the hands-on part of each chapter. An
+// exercise is a copy of a lesson's code with the important lines taken
+// out and replaced by TODOs, plus a set of checks the learner doesn't
+// get to see.
+//
+// Starting an exercise copies its stub into a workspace directory.
+// Checking it copies the learner's file next to the hidden checks in a
+// scratch directory and runs go test there.
+package exercises
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The stubs and checks live under testdata so the go tool leaves them
+// alone. The checks are kept as checks.go instead of checks_test.go so
+// they aren't mistaken for this repo's own tests. They only become a
+// _test.go file in the scratch directory Check builds.
+//
+//go:embed testdata
+var files embed.FS
+
+const (
+	stubFile   = "exercise.go"
+	checksFile = "checks.go"
+	goMod      = "module exercise\n\ngo 1.23\n"
+)
+
+// An Exercise is one stub and its checks
+type Exercise struct {
+	ID string // "<chapter>/<name>", like "interfaces/close"
+	// Lesson is the lesson the exercise is based on
+	Lesson string
+	Title  string
+}
+
+// exercises is every exercise, in book order
+var exercises = []Exercise{
+	{ID: "defer/reverse", Lesson: "defer/order", Title: "Reverse a list with defer"},
+	{ID: "panic/must", Lesson: "panic/division", Title: "Panic on a value that can't be wrong"},
+	{ID: "recover/safe-divide", Lesson: "recover/use", Title: "Turn a panic into an error"},
+	{ID: "pointers/swap", Lesson: "pointers/dereferencing", Title: "Change the caller's variables"},
+	{ID: "functions/variadic", Lesson: "functions/variadic-parameters", Title: "Variadic sums and averages"},
+	{ID: "interfaces/close", Lesson: "interfaces/composition", Title: "Implement BufferedWriterCloser.Close"},
+	{ID: "goroutines/mutexes", Lesson: "goroutines/mutexes", Title: "Lock a shared counter"},
+	{ID: "channels/pipeline", Lesson: "channels/range", Title: "Close the channels in a pipeline"},
+}
+
+// All returns every exercise
+func All() []Exercise {
+	return append([]Exercise(nil), exercises...)
+}
+
+// Lookup finds an exercise by its ID
+func Lookup(id string) (Exercise, bool) {
+	for _, e := range exercises {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Exercise{}, false
+}
+
+func (e Exercise) file(name string) ([]byte, error) {
+	return fs.ReadFile(files, path.Join("testdata", e.ID, name))
+}
+
+// Dir is where the exercise goes inside a workspace
+func (e Exercise) Dir(workspace string) string {
+	return filepath.Join(workspace, filepath.FromSlash(e.ID))
+}
+
+// ErrStarted is returned by Start when the exercise is already in the
+// workspace, so starting it again doesn't throw away someone's work
+var ErrStarted = errors.New("exercise already started")
+
+// Start copies the exercise's stub into the workspace and returns the
+// path of the file to edit. It won't overwrite a stub that's already
+// there unless force is set.
+func Start(workspace string, e Exercise, force bool) (string, error) {
+	dir := e.Dir(workspace)
+	stub := filepath.Join(dir, stubFile)
+	if _, err := os.Stat(stub); err == nil && !force {
+		return stub, ErrStarted
+	}
+	src, err := e.file(stubFile)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644); err != nil {
+		return "", err
+	}
+	return stub, os.WriteFile(stub, src, 0o644)
+}
+
+// A CheckResult is how one of the hidden checks went
+type CheckResult struct {
+	Name   string
+	Passed bool
+	// Output is what the check logged, which explains a failure
+	Output string
+}
+
+// A Report is the outcome of checking an exercise
+type Report struct {
+	Checks []CheckResult
+	// BuildOutput is set when the learner's code didn't compile, and
+	// holds the compiler's complaints
+	BuildOutput string
+}
+
+// Passed reports whether the exercise built and every check passed
+func (r Report) Passed() bool {
+	if r.BuildOutput != "" || len(r.Checks) == 0 {
+		return false
+	}
+	for _, c := range r.Checks {
+		if !c.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Check builds the learner's copy of the exercise with the hidden checks
+// and runs them. It needs the go command on the PATH.
+func Check(workspace string, e Exercise) (Report, error) {
+	src, err := os.ReadFile(filepath.Join(e.Dir(workspace), stubFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return Report{}, fmt.Errorf("%s hasn't been started, run exercise start %s first", e.ID, e.ID)
+	}
+	if err != nil {
+		return Report{}, err
+	}
+	checks, err := e.file(checksFile)
+	if err != nil {
+		return Report{}, err
+	}
+
+	dir, err := os.MkdirTemp("", "hellogo-check-")
+	if err != nil {
+		return Report{}, err
+	}
+	defer os.RemoveAll(dir)
+	for name, data := range map[string][]byte{
+		"go.mod":         []byte(goMod),
+		stubFile:         src,
+		"checks_test.go": checks,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			return Report{}, err
+		}
+	}
+
+	cmd := exec.Command("go", "test", "-json", "-count=1", ".")
+	cmd.Dir = dir
+	// Keep the learner's own go.work or GOFLAGS from getting involved
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return Report{}, fmt.Errorf("running go test: %w", err)
+	}
+	return parseTestOutput(out, stderr.String()), nil
+}
+
+// testEvent is a line of go test -json output
+type testEvent struct {
+	Action string
+	Test   string
+	Output string
+}
+
+// parseTestOutput turns go test -json output into a report. Anything
+// printed outside of a test, when no test ran at all, is the build
+// failing.
+func parseTestOutput(out []byte, stderr string) Report {
+	results := map[string]*CheckResult{}
+	var other strings.Builder
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		var ev testEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			other.WriteString(scanner.Text() + "\n")
+			continue
+		}
+		if ev.Test == "" {
+			if ev.Action == "output" || ev.Action == "build-output" {
+				other.WriteString(ev.Output)
+			}
+			continue
+		}
+		r, ok := results[ev.Test]
+		if !ok {
+			r = &CheckResult{Name: ev.Test}
+			results[ev.Test] = r
+		}
+		switch ev.Action {
+		case "output":
+			// Skip go test's own === RUN and --- FAIL lines
+			if !strings.HasPrefix(ev.Output, "=== ") && !strings.HasPrefix(strings.TrimSpace(ev.Output), "--- ") {
+				r.Output += ev.Output
+			}
+		case "pass":
+			r.Passed = true
+		}
+	}
+
+	var report Report
+	for _, r := range results {
+		report.Checks = append(report.Checks, *r)
+	}
+	sort.Slice(report.Checks, func(i, j int) bool { return report.Checks[i].Name < report.Checks[j].Name })
+	if len(report.Checks) == 0 {
+		report.BuildOutput = strings.TrimSpace(stderr + other.String())
+	}
+	return report
+}
@@ -0,0 +1,47 @@
+package exercise
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// collect receives everything from ch, failing the test if ch isn't
+// closed within a second
+func collect(t *testing.T, ch <-chan int) []int {
+	t.Helper()
+	if ch == nil {
+		t.Fatal("got a nil channel")
+	}
+	var got []int
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return got
+			}
+			got = append(got, v)
+		case <-timeout:
+			t.Fatalf("channel still open after receiving %v", got)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	if got, want := collect(t, Generate(5)), []int{1, 2, 3, 4, 5}; !slices.Equal(got, want) {
+		t.Errorf("Generate(5) sent %v, want %v", got, want)
+	}
+}
+
+func TestSquare(t *testing.T) {
+	if got, want := collect(t, Square(Generate(4))), []int{1, 4, 9, 16}; !slices.Equal(got, want) {
+		t.Errorf("Square(Generate(4)) sent %v, want %v", got, want)
+	}
+}
+
+func TestSquareNothing(t *testing.T) {
+	if got := collect(t, Square(Generate(0))); len(got) != 0 {
+		t.Errorf("Square(Generate(0)) sent %v, want nothing", got)
+	}
+}
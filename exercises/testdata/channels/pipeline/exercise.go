@@ -0,0 +1,27 @@
+// Exercise channels/pipeline
+//
+// A pipeline is a chain of goroutines connected by channels, each one
+// receiving values, doing something with them, and sending them on.
+// Ranging over a channel only stops once the channel is closed, so each
+// stage has to close its output when it's done.
+package exercise
+
+// Generate sends the numbers 1 through n on the channel it returns, and
+// closes the channel after the last one
+func Generate(n int) <-chan int {
+	out := make(chan int)
+	go func() {
+		for i := 1; i <= n; i++ {
+			out <- i
+		}
+		// TODO: tell receivers nothing more is coming
+	}()
+	return out
+}
+
+// Square receives every number from in and sends its square on the
+// channel it returns, closing that channel once in is closed
+func Square(in <-chan int) <-chan int {
+	// TODO
+	return nil
+}
@@ -0,0 +1,42 @@
+package exercise
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestPrintReversed(t *testing.T) {
+	var b strings.Builder
+	PrintReversed(&b, []string{"start", "middle", "end"})
+	if got, want := b.String(), "end\nmiddle\nstart\n"; got != want {
+		t.Errorf("PrintReversed printed %q, want %q", got, want)
+	}
+}
+
+func TestPrintReversedNothing(t *testing.T) {
+	var b strings.Builder
+	PrintReversed(&b, nil)
+	if b.Len() != 0 {
+		t.Errorf("PrintReversed printed %q for no words, want nothing", b.String())
+	}
+}
+
+func TestPrintReversedUsesDefer(t *testing.T) {
+	f, err := parser.ParseFile(token.NewFileSet(), "exercise.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	ast.Inspect(f, func(n ast.Node) bool {
+		if _, ok := n.(*ast.DeferStmt); ok {
+			found = true
+		}
+		return !found
+	})
+	if !found {
+		t.Error("PrintReversed doesn't use defer")
+	}
+}
@@ -0,0 +1,21 @@
+// Exercise defer/reverse
+//
+// Deferred calls run last in, first out, like a stack. Use that to
+// print a list of words backwards without walking the slice backwards
+// yourself.
+package exercise
+
+import (
+	"fmt"
+	"io"
+)
+
+// PrintReversed prints each word to w on its own line, last word first.
+// Don't index the slice from the end, let defer do the reversing.
+func PrintReversed(w io.Writer, words []string) {
+	for _, word := range words {
+		// TODO: this prints the words in order. Make the last one
+		// come out first.
+		fmt.Fprintln(w, word)
+	}
+}
@@ -0,0 +1,32 @@
+package exercise
+
+import "testing"
+
+func TestSum(t *testing.T) {
+	if got := Sum(1, 2, 3, 4, 5); got != 15 {
+		t.Errorf("Sum(1, 2, 3, 4, 5) = %d, want 15", got)
+	}
+	if got := Sum(); got != 0 {
+		t.Errorf("Sum() = %d, want 0", got)
+	}
+}
+
+func TestSumSpreadsASlice(t *testing.T) {
+	values := []int{10, 20, 30}
+	if got := Sum(values...); got != 60 {
+		t.Errorf("Sum(values...) = %d, want 60", got)
+	}
+}
+
+func TestAverage(t *testing.T) {
+	got, err := Average(1, 2, 3, 4)
+	if err != nil || got != 2.5 {
+		t.Errorf("Average(1, 2, 3, 4) = %v, %v, want 2.5, nil", got, err)
+	}
+}
+
+func TestAverageOfNothing(t *testing.T) {
+	if _, err := Average(); err == nil {
+		t.Error("Average() didn't return an error")
+	}
+}
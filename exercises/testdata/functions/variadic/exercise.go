@@ -0,0 +1,18 @@
+// Exercise functions/variadic
+//
+// A variadic parameter collects any number of arguments into a slice.
+// Errors are returned as the last of several return values.
+package exercise
+
+// Sum adds up all of its arguments. Sum() is 0.
+func Sum(values ...int) int {
+	// TODO
+	return 0
+}
+
+// Average returns the mean of its arguments, or an error if there
+// aren't any, since the mean of nothing isn't a number
+func Average(values ...float64) (float64, error) {
+	// TODO
+	return 0, nil
+}
@@ -0,0 +1,48 @@
+package exercise
+
+import (
+	"testing"
+	"time"
+)
+
+// waitsForLock calls fn while c's mutex is held and reports whether fn
+// waited for the mutex to be unlocked before finishing
+func waitsForLock(c *Counter, fn func()) bool {
+	c.mu.Lock()
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	select {
+	case <-done:
+		c.mu.Unlock()
+		return false
+	case <-time.After(50 * time.Millisecond):
+	}
+	c.mu.Unlock()
+	<-done
+	return true
+}
+
+func TestIncrementLocks(t *testing.T) {
+	c := &Counter{}
+	if !waitsForLock(c, c.Increment) {
+		t.Error("Increment changed the count without holding the mutex")
+	}
+}
+
+func TestValueLocks(t *testing.T) {
+	c := &Counter{}
+	if !waitsForLock(c, func() { c.Value() }) {
+		t.Error("Value read the count without holding the mutex")
+	}
+}
+
+func TestCountConcurrently(t *testing.T) {
+	c := &Counter{}
+	CountConcurrently(c, 1000)
+	if got := c.Value(); got != 1000 {
+		t.Errorf("after 1000 increments the count is %d", got)
+	}
+}
@@ -0,0 +1,37 @@
+// Exercise goroutines/mutexes
+//
+// Goroutines that change the same variable at the same time step on
+// each other, and some of their changes get lost. A mutex makes them
+// take turns, and a WaitGroup lets us wait until they've all finished.
+package exercise
+
+import (
+	"sync"
+)
+
+// A Counter can be incremented from many goroutines at once
+type Counter struct {
+	mu    sync.Mutex
+	count int
+}
+
+// Increment adds one to the count
+func (c *Counter) Increment() {
+	// TODO: only change count while holding the mutex
+	c.count++
+}
+
+// Value returns the count
+func (c *Counter) Value() int {
+	// TODO: only read count while holding the mutex
+	return c.count
+}
+
+// CountConcurrently increments c from n goroutines, one increment each,
+// and doesn't return until every one of them is done
+func CountConcurrently(c *Counter, n int) {
+	for i := 0; i < n; i++ {
+		// TODO: wait for these goroutines with a sync.WaitGroup
+		go c.Increment()
+	}
+}
@@ -0,0 +1,44 @@
+package exercise
+
+import (
+	"strings"
+	"testing"
+)
+
+var _ WriterCloser = NewBufferedWriterCloser(nil)
+
+func TestClosePrintsTheRest(t *testing.T) {
+	var b strings.Builder
+	wc := NewBufferedWriterCloser(&b)
+	wc.Write([]byte("Hello YouTube listeners, this is a test"))
+	if err := wc.Close(); err != nil {
+		t.Fatalf("Close returned %v", err)
+	}
+	want := "Hello Yo\nuTube li\nsteners,\n this is\n a test\n"
+	if got := b.String(); got != want {
+		t.Errorf("printed %q, want %q", got, want)
+	}
+}
+
+func TestCloseAfterExactlyEight(t *testing.T) {
+	var b strings.Builder
+	wc := NewBufferedWriterCloser(&b)
+	// Write holds on to the last 8 characters, so Close has to print
+	// all of them
+	wc.Write([]byte("12345678"))
+	wc.Close()
+	if got, want := b.String(), "12345678\n"; got != want {
+		t.Errorf("printed %q, want %q", got, want)
+	}
+}
+
+func TestCloseEmpty(t *testing.T) {
+	var b strings.Builder
+	wc := NewBufferedWriterCloser(&b)
+	if err := wc.Close(); err != nil {
+		t.Fatalf("Close returned %v", err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("closing an empty writer printed %q", b.String())
+	}
+}
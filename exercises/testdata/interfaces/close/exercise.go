@@ -0,0 +1,66 @@
+// Exercise interfaces/close
+//
+// BufferedWriterCloser is the WriterCloser from the interfaces chapter.
+// Write prints whatever is written to it 8 characters at a time, but it
+// stops while there are 8 or fewer characters left, since more might be
+// on the way. Close has to print what's left.
+package exercise
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+type Writer interface {
+	Write([]byte) (int, error)
+}
+
+type Closer interface {
+	Close() error
+}
+
+type WriterCloser interface {
+	Writer
+	Closer
+}
+
+// BufferedWriterCloser prints to out instead of the terminal, so the
+// checks can see what it printed
+type BufferedWriterCloser struct {
+	buffer *bytes.Buffer
+	out    io.Writer
+}
+
+func NewBufferedWriterCloser(out io.Writer) *BufferedWriterCloser {
+	return &BufferedWriterCloser{
+		buffer: bytes.NewBuffer([]byte{}),
+		out:    out,
+	}
+}
+
+func (bwc *BufferedWriterCloser) Write(data []byte) (int, error) {
+	n, err := bwc.buffer.Write(data)
+	if err != nil {
+		return 0, err
+	}
+	v := make([]byte, 8)
+	for bwc.buffer.Len() > 8 {
+		_, err := bwc.buffer.Read(v)
+		if err != nil {
+			return 0, err
+		}
+		_, err = fmt.Fprintln(bwc.out, string(v))
+		if err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+// Close prints everything still in the buffer, up to 8 characters per
+// line, so nothing that was written gets lost
+func (bwc *BufferedWriterCloser) Close() error {
+	// TODO
+	return nil
+}
@@ -0,0 +1,44 @@
+package exercise
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// panicMessage calls fn and returns what it panicked with, or "" if it
+// didn't panic
+func panicMessage(fn func()) (msg string) {
+	defer func() {
+		if p := recover(); p != nil {
+			msg = fmt.Sprint(p)
+			if msg == "" {
+				msg = "(empty panic)"
+			}
+		}
+	}()
+	fn()
+	return ""
+}
+
+func TestMustAgeParses(t *testing.T) {
+	if got := MustAge("42"); got != 42 {
+		t.Errorf("MustAge(%q) = %d, want 42", "42", got)
+	}
+}
+
+func TestMustAgePanicsOnGarbage(t *testing.T) {
+	msg := panicMessage(func() { MustAge("forty") })
+	if msg == "" {
+		t.Fatalf("MustAge(%q) didn't panic", "forty")
+	}
+	if !strings.Contains(msg, "forty") {
+		t.Errorf("MustAge(%q) panicked with %q, which doesn't say what the bad value was", "forty", msg)
+	}
+}
+
+func TestMustAgePanicsOnNegative(t *testing.T) {
+	if msg := panicMessage(func() { MustAge("-3") }); msg == "" {
+		t.Errorf("MustAge(%q) didn't panic", "-3")
+	}
+}
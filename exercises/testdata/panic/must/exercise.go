@@ -0,0 +1,21 @@
+// Exercise panic/must
+//
+// Go code rarely panics. Functions return errors and let the caller
+// decide what's fatal. The exception is a value that can only be wrong
+// if the program itself is wrong, like a constant in our own source.
+// Functions that panic on those are named MustXxx by convention.
+package exercise
+
+import (
+	"strconv"
+)
+
+// MustAge turns a string into an age. If the string isn't a number, or
+// the number is negative, it panics with a message that includes the
+// bad string so whoever sees the panic knows what to fix.
+func MustAge(s string) int {
+	age, err := strconv.Atoi(s)
+	// TODO: panic if err isn't nil or if age is negative
+	_ = err
+	return age
+}
@@ -0,0 +1,27 @@
+package exercise
+
+import "testing"
+
+func TestSwap(t *testing.T) {
+	a, b := 42, 27
+	Swap(&a, &b)
+	if a != 27 || b != 42 {
+		t.Errorf("after Swap, a = %d and b = %d, want 27 and 42", a, b)
+	}
+}
+
+func TestSwapSameVariable(t *testing.T) {
+	a := 13
+	Swap(&a, &a)
+	if a != 13 {
+		t.Errorf("swapping a with itself changed it to %d", a)
+	}
+}
+
+func TestMove(t *testing.T) {
+	p := Point{X: 1, Y: 2}
+	Move(&p, 3, -4)
+	if want := (Point{X: 4, Y: -2}); p != want {
+		t.Errorf("after Move, p = %+v, want %+v", p, want)
+	}
+}
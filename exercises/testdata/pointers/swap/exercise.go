@@ -0,0 +1,20 @@
+// Exercise pointers/swap
+//
+// Go passes everything by value, so a function only gets copies of its
+// arguments. To change the caller's variables, it needs their addresses.
+package exercise
+
+// Swap exchanges the values a and b point to
+func Swap(a, b *int) {
+	// TODO
+}
+
+// A Point is a spot on a grid
+type Point struct {
+	X, Y int
+}
+
+// Move shifts the point p points to by dx and dy
+func Move(p *Point, dx, dy int) {
+	// TODO
+}
@@ -0,0 +1,50 @@
+package exercise
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestSafeDivide(t *testing.T) {
+	got, err := SafeDivide(10, 2)
+	if err != nil || got != 5 {
+		t.Errorf("SafeDivide(10, 2) = %d, %v, want 5, nil", got, err)
+	}
+}
+
+func TestSafeDivideByZero(t *testing.T) {
+	defer func() {
+		if p := recover(); p != nil {
+			t.Errorf("SafeDivide(1, 0) panicked: %v", p)
+		}
+	}()
+	_, err := SafeDivide(1, 0)
+	if err == nil {
+		t.Fatal("SafeDivide(1, 0) didn't return an error")
+	}
+	if !strings.Contains(err.Error(), "divide by zero") {
+		t.Errorf("SafeDivide(1, 0) returned %q, want it to mention the divide by zero", err)
+	}
+}
+
+func TestSafeDivideUsesRecover(t *testing.T) {
+	f, err := parser.ParseFile(token.NewFileSet(), "exercise.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	ast.Inspect(f, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if id, ok := call.Fun.(*ast.Ident); ok && id.Name == "recover" {
+				found = true
+			}
+		}
+		return !found
+	})
+	if !found {
+		t.Error("SafeDivide doesn't call recover")
+	}
+}
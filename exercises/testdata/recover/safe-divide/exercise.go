@@ -0,0 +1,13 @@
+// Exercise recover/safe-divide
+//
+// Dividing an integer by zero panics. A deferred function still runs
+// while a panic unwinds the stack, and recover inside it stops the
+// panic. Use that to turn the panic into an ordinary error.
+package exercise
+
+// SafeDivide returns a / b. If b is zero it returns an error instead of
+// panicking. Don't check b yourself, recover from the panic.
+func SafeDivide(a, b int) (result int, err error) {
+	// TODO: defer a function that recovers from the panic and sets err
+	return a / b, nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+// exploreCommand runs each lesson many times, each in a fresh copy of
+// this program, and shows how often each distinct output came up.
+// Concurrent lessons print in a different order depending on how the
+// scheduler feels, and that's exactly what they're trying to teach, so
+// seeing the spread beats seeing one run.
+func exploreCommand(args []string) error {
+	fs := newFlagSet("explore", "lessons...")
+	f := filterFlags(fs)
+	runs := fs.Int("runs", 50, "how many times to run each lesson")
+	var procs listFlag
+	fs.Var(&procs, "procs", "GOMAXPROCS values to sweep, like 1,2,4 (default is hellogo's own)")
+	parallel := fs.Int("parallel", runtime.GOMAXPROCS(0), "how many runs to have going at once")
+	timeout := fs.Duration("timeout", lessons.DefaultOptions.Timeout, "how long each run gets")
+	ls, err := selectLessons(fs, f, args)
+	if err != nil {
+		return err
+	}
+	if len(f.Patterns) == 0 {
+		return fmt.Errorf("explore needs at least one lesson, like goroutines/creation")
+	}
+	if *runs < 1 || *parallel < 1 {
+		return fmt.Errorf("--runs and --parallel have to be at least 1")
+	}
+	for _, p := range procs {
+		if n, err := strconv.Atoi(p); err != nil || n < 1 {
+			return fmt.Errorf("bad --procs value %q", p)
+		}
+	}
+	if len(procs) == 0 {
+		// GOMAXPROCS(0) takes the environment into account, unlike
+		// NumCPU, and passing it on means every run gets the same value
+		// no matter what the children would have worked out themselves
+		procs = listFlag{strconv.Itoa(runtime.GOMAXPROCS(0))}
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	for _, l := range ls {
+		for _, p := range procs {
+			setting := "GOMAXPROCS=" + p
+			outputs := explore(self, l, p, *runs, *parallel, *timeout)
+			printHistogram(l, setting, outputs, *runs)
+		}
+	}
+	return nil
+}
+
+// explore runs a lesson n times in subprocesses and counts how many
+// times each output came up
+func explore(self string, l lessons.Lesson, procs string, n, parallel int, timeout time.Duration) map[string]int {
+	var (
+		mu     sync.Mutex
+		counts = map[string]int{}
+		wg     sync.WaitGroup
+		jobs   = make(chan struct{})
+	)
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				out := runOnce(self, l, procs, timeout)
+				mu.Lock()
+				counts[out]++
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- struct{}{}
+	}
+	close(jobs)
+	wg.Wait()
+	return counts
+}
+
+// runOnce runs a single lesson in a new process and returns its output
+// with addresses and timestamps scrubbed out, so only real differences
+// count. How the process ended is part of the output, since a run that
+// crashes is a different outcome from one that doesn't.
+func runOnce(self string, l lessons.Lesson, procs string, timeout time.Duration) string {
+	// The lesson's own timeout should go off first, with a little room
+	// for the process to start and stop
+	ctx, cancel := context.WithTimeout(context.Background(), timeout+5*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, self, "run", "--raw", "--timeout", timeout.String(), l.ID)
+	cmd.Env = append(os.Environ(), "GOMAXPROCS="+procs)
+	out, err := cmd.Output()
+	result := lessons.Scrub(string(out))
+	if err != nil {
+		result += fmt.Sprintf("(%v)\n", err)
+	}
+	return result
+}
+
+func printHistogram(l lessons.Lesson, setting string, counts map[string]int, runs int) {
+	type variant struct {
+		output string
+		count  int
+	}
+	var variants []variant
+	for out, n := range counts {
+		variants = append(variants, variant{out, n})
+	}
+	sort.Slice(variants, func(i, j int) bool {
+		if variants[i].count != variants[j].count {
+			return variants[i].count > variants[j].count
+		}
+		return variants[i].output < variants[j].output
+	})
+
+	fmt.Printf("%s with %s: %d runs, %d distinct output(s)\n", l.ID, setting, runs, len(variants))
+	for _, v := range variants {
+		pct := float64(v.count) * 100 / float64(runs)
+		fmt.Printf("\n%5d  %5.1f%%  %s\n", v.count, pct, strings.Repeat("#", max(1, int(pct/2))))
+		for _, line := range strings.Split(strings.TrimSuffix(v.output, "\n"), "\n") {
+			fmt.Printf("         | %s\n", line)
+		}
+	}
+	fmt.Println(lessons.Separator)
+}
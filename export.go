@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nicolasjhampton/hellogo/book"
+)
+
+// bookFormats are the ways export can write the book, keyed by the name
+// used with --format
+var bookFormats = map[string]struct {
+	write func(dir string, pages []book.Page) error
+	index string
+}{
+	"markdown": {book.WriteMarkdown, "index.md"},
+	"html":     {book.WriteHTML, "index.html"},
+}
+
+// exportCommand runs the lessons and writes them out as a book, with a
+// directory under --out for each format
+func exportCommand(args []string) error {
+	fs := newFlagSet("export", "[lessons...]")
+	f := filterFlags(fs)
+	opts := runnerFlags(fs)
+	out := fs.String("out", "_book", "directory to write the book into")
+	var formats listFlag
+	fs.Var(&formats, "format", "formats to write, markdown and/or html (default both)")
+	ls, err := selectLessons(fs, f, args)
+	if err != nil {
+		return err
+	}
+	// The default is filled in after parsing, since listFlag adds to
+	// what's already there and --format=html has to mean only html
+	if len(formats) == 0 {
+		formats = listFlag{"markdown", "html"}
+	}
+	for _, name := range formats {
+		if _, ok := bookFormats[name]; !ok {
+			return fmt.Errorf("unknown format %q, want markdown or html", name)
+		}
+	}
+
+	pages, err := book.Build(ls, *opts)
+	if err != nil {
+		return err
+	}
+	for _, name := range formats {
+		format := bookFormats[name]
+		dir := filepath.Join(*out, name)
+		if err := format.write(dir, pages); err != nil {
+			return err
+		}
+		fmt.Printf("wrote %s\n", filepath.Join(dir, format.index))
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package functions
+
+import "testing"
+
+// These benchmarks back the claims in claims.go. Run them with
+// hellogo bench to see the numbers under the comments they check.
+
+// sink and greeting keep the compiler from throwing away results
+// nobody uses. greeting is a string so storing one doesn't allocate.
+var (
+	sink     any
+	greeting string
+)
+
+//go:noinline
+func sumByValue(values ...int) int {
+	result := 0
+	for _, v := range values {
+		result += v
+	}
+	return result
+}
+
+//go:noinline
+func sumByPointer(values ...int) *int {
+	result := 0
+	for _, v := range values {
+		result += v
+	}
+	return &result
+}
+
+func BenchmarkSumByValue(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		sink = sumByValue(1, 2, 3, 4, 5)
+	}
+}
+
+func BenchmarkSumByPointer(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		sink = sumByPointer(1, 2, 3, 4, 5)
+	}
+}
+
+// A value big enough that copying it might actually matter
+type big [1024]int
+
+//go:noinline
+func bigByValue() big {
+	var b big
+	b[0] = 1
+	return b
+}
+
+//go:noinline
+func bigByPointer() *big {
+	var b big
+	b[0] = 1
+	return &b
+}
+
+func BenchmarkBigByValue(b *testing.B) {
+	first := 0
+	for i := 0; i < b.N; i++ {
+		first += bigByValue()[0]
+	}
+	sink = first
+}
+
+func BenchmarkBigByPointer(b *testing.B) {
+	first := 0
+	for i := 0; i < b.N; i++ {
+		first += bigByPointer()[0]
+	}
+	sink = first
+}
+
+// greet without the Println, once with each kind of receiver
+
+//go:noinline
+func (g greeter) valueGreeting() string {
+	return g.greeting
+}
+
+//go:noinline
+func (g *greeter) pointerGreeting() string {
+	return g.greeting
+}
+
+func BenchmarkValueReceiver(b *testing.B) {
+	g := greeter{greeting: "hello", name: "go"}
+	for i := 0; i < b.N; i++ {
+		greeting = g.valueGreeting()
+	}
+}
+
+func BenchmarkPointerReceiver(b *testing.B) {
+	g := greeter{greeting: "hello", name: "go"}
+	for i := 0; i < b.N; i++ {
+		greeting = g.pointerGreeting()
+	}
+}
@@ -0,0 +1,34 @@
+package functions
+
+import "github.com/nicolasjhampton/hellogo/lessons"
+
+// These claims are checked by the benchmarks in bench_test.go, which
+// time quiet copies of the lesson functions, without the Println, since
+// printing would swamp whatever difference the claim is about.
+
+func init() {
+	lessons.RegisterClaims(
+		lessons.Claim{
+			ID:      "functions/sum-three",
+			Title:   "Returning a pointer instead of a value",
+			Near:    sumThree,
+			Comment: "an expensive copy of a value",
+			Benchmarks: []lessons.Benchmark{
+				{Name: "int returned by value (sumTwo)", Func: "BenchmarkSumByValue"},
+				{Name: "int returned as a pointer (sumThree)", Func: "BenchmarkSumByPointer"},
+				{Name: "8KB array returned by value", Func: "BenchmarkBigByValue"},
+				{Name: "8KB array returned as a pointer", Func: "BenchmarkBigByPointer"},
+			},
+		},
+		lessons.Claim{
+			ID:      "functions/pointer-receivers",
+			Title:   "Pointer receivers on greeter",
+			Near:    functionMethods,
+			Comment: "this method will use more memory every call",
+			Benchmarks: []lessons.Benchmark{
+				{Name: "value receiver", Func: "BenchmarkValueReceiver"},
+				{Name: "pointer receiver", Func: "BenchmarkPointerReceiver"},
+			},
+		},
+	)
+}
@@ -2,24 +2,33 @@ package functions
 
 import (
 	"fmt"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
 )
 
-var functionLessons = []func(){
-	functionSyntax,
-	functionParameters,
-	functionVariadicParameters,
-	functionReturn,
-	functionReturns,
-	functionAnon,
-	functionMethods,
+var functionLessons = []lessons.Lesson{
+	{ID: "functions/syntax", Title: "Function syntax", Run: functionSyntax},
+	{ID: "functions/parameters", Title: "Value and pointer parameters", Run: functionParameters, Teaches: []string{"pointer parameters"}, Requires: []string{"dereferencing"}, Quiz: &lessons.Quiz{
+		Question: "The last six lines come from sayGreetingTwo, the Println after it, and sayGreetingThree. What are they?",
+		Tail:     6,
+		Distractors: []string{
+			"hello Stacey\nPeter\nPeter\nhello Peter\nPeter\nPeter",
+			"hello Stacey\nPeter\nStacey\nhello Stacey\nPeter\nStacey",
+		},
+	}},
+	{ID: "functions/variadic-parameters", Title: "Variadic parameters", Run: functionVariadicParameters, Teaches: []string{"variadic functions"}},
+	{ID: "functions/return", Title: "Return values", Run: functionReturn, Requires: []string{"variadic functions", "dereferencing"}},
+	{ID: "functions/returns", Title: "Returning an error", Run: functionReturns, Teaches: []string{"errors"}},
+	{ID: "functions/anon", Title: "Anonymous functions", Run: functionAnon, Teaches: []string{"anonymous functions"}},
+	{ID: "functions/methods", Title: "Methods", Run: functionMethods, Teaches: []string{"methods", "pointer receivers"}, Requires: []string{"pointers to structs"}},
+}
+
+func init() {
+	lessons.Register(lessons.Chapter{Name: "functions", Title: "FUNCTIONS", Order: 70}, functionLessons...)
 }
 
 func FunctionLessons() {
-	fmt.Println("////////////////////////////*FUNCTIONS*////////////////////////////")
-	for _, lesson := range functionLessons {
-		lesson()
-		fmt.Println("------------------------------------------------------------------")
-	}
+	lessons.RunChapter("functions")
 }
 
 // A function declaration has to have the func keyword and the paranthesis
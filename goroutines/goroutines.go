@@ -5,20 +5,23 @@ import (
 	"time"
 	"sync"
 	"runtime"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
 )
 
-var goroutineLessons = []func(){
-	goroutineCreation,
-	goroutineWaitGroups,
-	goroutineMutexes,
+var goroutineLessons = []lessons.Lesson{
+	{ID: "goroutines/creation", Title: "Starting a goroutine", Run: goroutineCreation, Teaches: []string{"goroutines"}, Requires: []string{"anonymous functions"}, FixedBy: "goroutines/creation-fixed"},
+	{ID: "goroutines/creation-fixed", Title: "Passing values into a goroutine", Run: goroutineCreationFixed, Requires: []string{"goroutines/creation"}},
+	{ID: "goroutines/wait-groups", Title: "WaitGroups", Run: goroutineWaitGroups, Teaches: []string{"waitgroups"}, Requires: []string{"goroutines"}},
+	{ID: "goroutines/mutexes", Title: "Mutexes", Run: goroutineMutexes, Teaches: []string{"mutexes"}, Requires: []string{"waitgroups"}},
+}
+
+func init() {
+	lessons.Register(lessons.Chapter{Name: "goroutines", Title: "GOROUTINES", Order: 90}, goroutineLessons...)
 }
 
 func GoroutineLessons() {
-	fmt.Println("////////////////////////////*GOROUTINES*////////////////////////////")
-	for _, lesson := range goroutineLessons {
-		lesson()
-		fmt.Println("------------------------------------------------------------------")
-	}
+	lessons.RunChapter("goroutines")
 }
 
 func goroutineCreation() {
@@ -76,6 +79,19 @@ func goroutineCreation() {
 	// exit status 66
 }
 
+// This is the race from goroutineCreation with the fix its comments
+// describe. msg is passed in as an argument, so the goroutine gets its
+// own copy and changing msg afterwards can't touch it. This one prints
+// "Hello", and `go run -race .` has nothing to say about it.
+func goroutineCreationFixed() {
+	var msg = "Hello"
+	go func(msg string) {
+		fmt.Println(msg)
+	}(msg)
+	msg = "Goodbye"
+	time.Sleep(100 * time.Millisecond)
+}
+
 var wg = sync.WaitGroup{}
 
 // Instead of setting sleep timers for synchronization, we can set a
@@ -0,0 +1,41 @@
+package interfaces
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Both benchmarks drain the same 64KB buffer 8 bytes at a time, the way
+// Write and Close do, minus the printing
+
+var drainData = bytes.Repeat([]byte("Hello YouTube listeners, Go is good for you. "), 64*1024/46)
+
+func BenchmarkBufferRead(b *testing.B) {
+	benchDrain(b, func(buf *bytes.Buffer, v []byte) int {
+		n, _ := buf.Read(v)
+		return n
+	})
+}
+
+func BenchmarkBufferNext(b *testing.B) {
+	benchDrain(b, func(buf *bytes.Buffer, v []byte) int {
+		return len(buf.Next(8))
+	})
+}
+
+func benchDrain(b *testing.B, read func(buf *bytes.Buffer, v []byte) int) {
+	b.SetBytes(int64(len(drainData)))
+	var buf bytes.Buffer
+	v := make([]byte, 8)
+	total := 0
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		buf.Write(drainData)
+		for buf.Len() > 0 {
+			total += read(&buf, v)
+		}
+	}
+	if total != b.N*len(drainData) {
+		b.Fatalf("read %d bytes, want %d", total, b.N*len(drainData))
+	}
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import "github.com/nicolasjhampton/hellogo/lessons"
+
+// This claim is the guess in BufferedWriterCloser.Write about
+// buffer.Next and buffer.Read, checked by the benchmarks in
+// bench_test.go
+
+func init() {
+	lessons.RegisterClaims(lessons.Claim{
+		ID:      "interfaces/buffer-next",
+		Title:   "buffer.Next against buffer.Read",
+		Near:    interfaceComposition,
+		Comment: "My guess is Next is less efficent than",
+		Benchmarks: []lessons.Benchmark{
+			{Name: "Read into an 8 byte slice (Write)", Func: "BenchmarkBufferRead"},
+			{Name: "Next(8) (Close)", Func: "BenchmarkBufferNext"},
+		},
+	})
+}
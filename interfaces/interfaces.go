@@ -4,25 +4,29 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
 )
 
-var interfaceLessons = []func(){
-	interfaceBasics,
-	interfaceOnOtherTypes,
-	interfaceComposition,
-	interfaceTypeConversion,
-	interfaceConversionPanics,
-	interfaceEmpty,
-	interfaceSwitching,
-	interfaceReferenceReceiver,
+var interfaceLessons = []lessons.Lesson{
+	{ID: "interfaces/basics", Title: "Interface basics", Run: interfaceBasics, Teaches: []string{"interfaces"}, Requires: []string{"methods"}},
+	{ID: "interfaces/on-other-types", Title: "Interfaces on other types", Run: interfaceOnOtherTypes, Requires: []string{"interfaces", "pointer receivers", "dereferencing"}},
+	{ID: "interfaces/composition", Title: "Composing interfaces", Run: interfaceComposition, Teaches: []string{"interface embedding"}, Requires: []string{"interfaces", "pointer receivers"}},
+	{ID: "interfaces/type-conversion", Title: "Type conversion", Run: interfaceTypeConversion, Teaches: []string{"type assertions"}, Requires: []string{"interface embedding"}},
+	{ID: "interfaces/conversion-panics", Title: "Checking a conversion", Run: interfaceConversionPanics, Requires: []string{"type assertions", "panic"}},
+	{ID: "interfaces/empty", Title: "The empty interface", Run: interfaceEmpty, Teaches: []string{"empty interface"}, Requires: []string{"interfaces"}},
+	{ID: "interfaces/switching", Title: "Type switches", Run: interfaceSwitching, Requires: []string{"empty interface"}, Quiz: &lessons.Quiz{
+		Distractors: []string{"i is a integer", "I don't know what i is"},
+	}},
+	{ID: "interfaces/reference-receiver", Title: "Method sets and pointer receivers", Run: interfaceReferenceReceiver, Teaches: []string{"method sets"}, Requires: []string{"pointer receivers", "interface embedding"}},
+}
+
+func init() {
+	lessons.Register(lessons.Chapter{Name: "interfaces", Title: "INTERFACES", Order: 80}, interfaceLessons...)
 }
 
 func InterfaceLessons() {
-	fmt.Println("////////////////////////////*INTERFACES*////////////////////////////")
-	for _, lesson := range interfaceLessons {
-		lesson()
-		fmt.Println("------------------------------------------------------------------")
-	}
+	lessons.RunChapter("interfaces")
 }
 
 // The interface defines BEHAVIOR
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+	"github.com/nicolasjhampton/hellogo/progress"
+)
+
+// learnerOptions say whose progress to record and where
+type learnerOptions struct {
+	path string
+	user string
+	off  bool
+
+	// mu keeps recording to one at a time, since the playground records
+	// from every request, and each one reads the file, adds to it, and
+	// writes it back
+	mu sync.Mutex
+}
+
+// learnerFlags adds the flags for commands that read or record progress
+func learnerFlags(fs *flag.FlagSet) *learnerOptions {
+	o := &learnerOptions{}
+	fs.StringVar(&o.path, "progress-file", progress.DefaultPath(), "file progress is kept in")
+	fs.StringVar(&o.user, "user", progress.CurrentUser(), "whose progress to use")
+	fs.BoolVar(&o.off, "no-progress", false, "don't record progress")
+	return o
+}
+
+func (o *learnerOptions) load() (*progress.State, *progress.Learner, error) {
+	state, err := progress.Load(o.path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading progress: %w", err)
+	}
+	return state, state.Learner(o.user), nil
+}
+
+// record adds lesson results to the learner's progress
+func (o *learnerOptions) record(results ...lessons.Result) error {
+	if o.off || len(results) == 0 {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	state, learner, err := o.load()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, res := range results {
+		learner.Record(res, now)
+	}
+	return o.save(state)
+}
+
+// save writes the progress file, saying where the first time, since
+// running lessons is all it takes to create it
+func (o *learnerOptions) save(state *progress.State) error {
+	if _, err := os.Stat(o.path); errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "Saving your progress to %s (--no-progress turns this off)\n", o.path)
+	}
+	if err := state.Save(); err != nil {
+		return fmt.Errorf("saving progress: %w", err)
+	}
+	return nil
+}
+
+// progressCommand shows how much of each chapter the learner has done
+func progressCommand(args []string) error {
+	fs := newFlagSet("progress", "")
+	o := learnerFlags(fs)
+	verbose := fs.Bool("v", false, "list every lesson, not just the chapters")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	_, learner, err := o.load()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Progress for %s\n\n", o.user)
+	total, done := 0, 0
+	for _, c := range lessons.Chapters() {
+		if len(c.Lessons) == 0 {
+			continue
+		}
+		n := 0
+		for _, l := range c.Lessons {
+			if learner.Completed(l.ID) {
+				n++
+			}
+		}
+		total += len(c.Lessons)
+		done += n
+		fmt.Fprintf(w, "%s\t%d/%d\t%s\n", c.Name, n, len(c.Lessons), bar(n, len(c.Lessons)))
+		if *verbose {
+			for _, l := range c.Lessons {
+				fmt.Fprintf(w, "  [%s] %s\t%s\t\n", mark(learner, l.ID), l.ID, lastRun(learner, l.ID))
+			}
+		}
+	}
+	fmt.Fprintf(w, "\ntotal\t%d/%d\t%s\n", done, total, bar(done, total))
+	return w.Flush()
+}
+
+func bar(n, total int) string {
+	const width = 20
+	filled := 0
+	if total > 0 {
+		filled = n * width / total
+	}
+	return "[" + strings.Repeat("#", filled) + strings.Repeat(".", width-filled) + "]"
+}
+
+func mark(learner *progress.Learner, id string) string {
+	if learner.Completed(id) {
+		return "x"
+	}
+	return " "
+}
+
+func lastRun(learner *progress.Learner, id string) string {
+	r, ok := learner.Lessons[id]
+	if !ok {
+		return "not started"
+	}
+	return fmt.Sprintf("%s %s", r.LastStatus, r.LastRun.Format("2006-01-02 15:04"))
+}
+
+// continueCommand picks up at the first lesson the learner hasn't
+// finished and runs from there to the end of the book
+func continueCommand(args []string) error {
+	fs := newFlagSet("continue", "")
+	opts := runnerFlags(fs)
+	o := learnerFlags(fs)
+	step := fs.Bool("step", false, "walk through the lessons one at a time, like the step command")
+	format := formatFlag(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	_, learner, err := o.load()
+	if err != nil {
+		return err
+	}
+	// With --format=json, stdout is nothing but events, one per line
+	msgs := os.Stdout
+	if *format == "json" {
+		msgs = os.Stderr
+	}
+	next, ok := learner.NextLesson(lessons.All())
+	if !ok {
+		fmt.Fprintln(msgs, "You've finished every lesson!")
+		return nil
+	}
+	ls, err := lessons.Filter{From: next.ID}.Select(lessons.All())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(msgs, "Picking up at %s: %s\n", next.ID, next.Title)
+
+	if *step {
+		return runStepper(ls, *opts, o)
+	}
+	results, err := runFormatted(ls, *opts, *format)
+	if err != nil {
+		return err
+	}
+	// Not being able to save progress, like with a read-only config
+	// directory, shouldn't fail the lessons themselves
+	if err := o.record(results...); err != nil {
+		fmt.Fprintln(os.Stderr, "hellogo:", err)
+	}
+	return lessons.Summarize(results)
+}
@@ -0,0 +1,44 @@
+package lessons
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// captureMu makes sure only one capture swaps os.Stdout at a time
+var captureMu sync.Mutex
+
+// Capture runs fn with os.Stdout pointed at a pipe, and copies whatever
+// fn prints into w. Lessons print with fmt.Println, which looks up
+// os.Stdout on every call, so swapping the variable is enough to catch
+// their output. Anything a lesson's goroutines print after fn returns
+// goes back to the real stdout.
+func Capture(w io.Writer, fn func()) (err error) {
+	captureMu.Lock()
+	defer captureMu.Unlock()
+
+	r, pw, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	copied := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(w, r)
+		r.Close()
+		copied <- err
+	}()
+
+	stdout := os.Stdout
+	os.Stdout = pw
+	// Putting stdout back is deferred so it still happens if fn panics
+	defer func() {
+		os.Stdout = stdout
+		pw.Close()
+		if cerr := <-copied; err == nil {
+			err = cerr
+		}
+	}()
+	fn()
+	return nil
+}
@@ -0,0 +1,121 @@
+package lessons
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// A Claim is something a lesson's comments say about performance, like
+// "returning a pointer avoids an expensive copy", along with benchmarks
+// that put it to the test. Chapters register them next to their
+// lessons, and the bench command runs the benchmarks with go test and
+// shows the numbers next to the comment that made the claim.
+type Claim struct {
+	// ID is "<chapter>/<name>", like a lesson's
+	ID    string
+	Title string
+	// Near is any function in the file the claim is made in, and
+	// Comment is a bit of the comment making it. Together they're
+	// enough to find the comment to show the results under.
+	Near    any
+	Comment string
+	// Benchmarks live in the _test.go files of the package Near is
+	// in, so the testing package stays out of hellogo itself
+	Benchmarks []Benchmark
+}
+
+// A Benchmark is one side of a claim
+type Benchmark struct {
+	// Name is what the results are labeled with
+	Name string
+	// Func is the name of the benchmark function, like BenchmarkSum
+	Func string
+}
+
+// A ClaimComment is the comment a claim was found in
+type ClaimComment struct {
+	File string
+	Line int
+	// Text is the whole comment, exactly as it is in the file
+	Text string
+}
+
+var (
+	claims     []Claim
+	claimsByID = map[string]bool{}
+)
+
+// RegisterClaims adds claims to the registry. Like Register, it panics
+// on mistakes so they show up as soon as the program starts.
+func RegisterClaims(cs ...Claim) {
+	for _, c := range cs {
+		if !strings.Contains(c.ID, "/") {
+			panic(fmt.Sprintf("lessons: claim %q should be <chapter>/<name>", c.ID))
+		}
+		if claimsByID[c.ID] {
+			panic(fmt.Sprintf("lessons: claim %q registered twice", c.ID))
+		}
+		if len(c.Benchmarks) == 0 {
+			panic(fmt.Sprintf("lessons: claim %q has no benchmarks", c.ID))
+		}
+		for _, b := range c.Benchmarks {
+			if !strings.HasPrefix(b.Func, "Benchmark") {
+				panic(fmt.Sprintf("lessons: claim %q has a benchmark %q that go test won't run", c.ID, b.Func))
+			}
+		}
+		claimsByID[c.ID] = true
+		claims = append(claims, c)
+	}
+}
+
+// Claims returns every registered claim, in the order of the chapters
+// they belong to, and in the order they were registered within one
+func Claims() []Claim {
+	order := map[string]int{}
+	for _, c := range Chapters() {
+		order[c.Name] = c.Order
+	}
+	cs := append([]Claim(nil), claims...)
+	sort.SliceStable(cs, func(i, j int) bool {
+		ci, _, _ := strings.Cut(cs[i].ID, "/")
+		cj, _, _ := strings.Cut(cs[j].ID, "/")
+		return order[ci] < order[cj]
+	})
+	return cs
+}
+
+// Package is the directory of the package the claim's benchmarks are
+// in, relative to the root of the repo, like ./functions
+func (c Claim) Package() (string, error) {
+	file, _, err := funcLine(c.Near)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", c.ID, err)
+	}
+	dir := path.Dir(RepoPath(file))
+	if path.IsAbs(dir) {
+		return dir, nil
+	}
+	return "./" + dir, nil
+}
+
+// Source finds the comment that makes the claim
+func (c Claim) Source() (ClaimComment, error) {
+	file, _, err := funcLine(c.Near)
+	if err != nil {
+		return ClaimComment{}, fmt.Errorf("%s: %w", c.ID, err)
+	}
+	pf, err := parseFile(file)
+	if err != nil {
+		return ClaimComment{}, fmt.Errorf("%s: %w", c.ID, err)
+	}
+	for _, group := range pf.file.Comments {
+		start, end := pf.fset.Position(group.Pos()), pf.fset.Position(group.End())
+		text := string(pf.src[start.Offset:end.Offset])
+		if strings.Contains(text, c.Comment) {
+			return ClaimComment{File: start.Filename, Line: start.Line, Text: text}, nil
+		}
+	}
+	return ClaimComment{}, fmt.Errorf("%s: no comment in %s says %q", c.ID, RepoPath(file), c.Comment)
+}
@@ -0,0 +1,118 @@
+package lessons
+
+import (
+	"fmt"
+	"path"
+)
+
+// A Filter picks lessons out of the book. The zero Filter picks every
+// lesson.
+type Filter struct {
+	// Patterns are lesson IDs, globs like "interfaces/*", or chapter
+	// names. If there are none, every lesson matches.
+	Patterns []string
+	// Chapters limits the selection to these chapters
+	Chapters []string
+	// Skip leaves out lessons matching any of these IDs or globs
+	Skip []string
+	// From starts the selection at this lesson ID, dropping everything
+	// that comes before it in book order
+	From string
+}
+
+// Select returns the lessons from ls that the filter picks, keeping the
+// order of ls. A pattern that doesn't match anything is an error, since
+// it's almost always a typo, and so is one whose matches are all
+// skipped, since that leaves it with nothing to do.
+func (f Filter) Select(ls []Lesson) ([]Lesson, error) {
+	for _, name := range f.Chapters {
+		if _, ok := LookupChapter(name); !ok {
+			return nil, fmt.Errorf("no chapter named %q", name)
+		}
+	}
+	for _, p := range append(f.Patterns, f.Skip...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("bad pattern %q: %w", p, err)
+		}
+	}
+	if f.From != "" {
+		start := -1
+		for i, l := range ls {
+			if l.ID == f.From {
+				start = i
+				break
+			}
+		}
+		if start < 0 {
+			return nil, fmt.Errorf("no lesson %q to start from", f.From)
+		}
+		ls = ls[start:]
+	}
+
+	// used says which patterns matched anything at all, and kept which
+	// matched something --skip didn't take away again, so the two
+	// mistakes get different errors
+	used := make([]bool, len(f.Patterns))
+	kept := make([]bool, len(f.Patterns))
+	var selected []Lesson
+	for _, l := range ls {
+		if len(f.Chapters) > 0 && !contains(f.Chapters, l.Chapter) {
+			continue
+		}
+		skipped := matchAny(f.Skip, l)
+		if len(f.Patterns) > 0 {
+			matched := false
+			for i, p := range f.Patterns {
+				if match(p, l) {
+					used[i] = true
+					kept[i] = kept[i] || !skipped
+					matched = true
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+		if skipped {
+			continue
+		}
+		selected = append(selected, l)
+	}
+	for i, p := range f.Patterns {
+		if !used[i] {
+			return nil, fmt.Errorf("no lesson matches %q", p)
+		}
+		if !kept[i] {
+			return nil, fmt.Errorf("every lesson matching %q is skipped", p)
+		}
+	}
+	return selected, nil
+}
+
+// match reports whether pattern picks the lesson, either as a glob
+// against its ID or as the name of its chapter
+func match(pattern string, l Lesson) bool {
+	if pattern == l.Chapter {
+		return true
+	}
+	ok, _ := path.Match(pattern, l.ID)
+	return ok
+}
+
+func matchAny(patterns []string, l Lesson) bool {
+	for _, p := range patterns {
+		if match(p, l) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
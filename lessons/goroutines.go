@@ -0,0 +1,117 @@
+package lessons
+
+import (
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Goroutine is one entry from a dump of every running goroutine
+type Goroutine struct {
+	ID int
+	// State is what the goroutine is doing, like "chan receive" or
+	// "select"
+	State string
+	// Stack is the goroutine's whole entry from the dump, header included
+	Stack string
+	// Note is filled in when a timed out lesson is stuck in this
+	// goroutine, and says where and on what
+	Note string
+}
+
+// A Frame is one function call in a goroutine's stack
+type Frame struct {
+	Func string // like github.com/nicolasjhampton/hellogo/channels.channelRange.func1
+	File string
+	Line int
+}
+
+// Frames returns the calls in the goroutine's stack, innermost first.
+// The "created by" entry at the bottom isn't included.
+func (g Goroutine) Frames() []Frame {
+	var frames []Frame
+	lines := strings.Split(g.Stack, "\n")
+	// Past the header, each call takes two lines: the function, then a
+	// tab indented file:line
+	for i := 1; i+1 < len(lines); i += 2 {
+		fn, loc := lines[i], strings.TrimSpace(lines[i+1])
+		if strings.HasPrefix(fn, "created by ") {
+			break
+		}
+		if j := strings.LastIndex(fn, "("); j > 0 {
+			fn = fn[:j]
+		}
+		// Drop the "+0x1f" offset after the line number
+		loc, _, _ = strings.Cut(loc, " ")
+		file, line := loc, 0
+		if j := strings.LastIndex(loc, ":"); j > 0 {
+			file = loc[:j]
+			line, _ = strconv.Atoi(loc[j+1:])
+		}
+		frames = append(frames, Frame{Func: fn, File: file, Line: line})
+	}
+	return frames
+}
+
+var goroutineHeader = regexp.MustCompile(`^goroutine (\d+) \[([^\]]*)\]`)
+
+// dumpGoroutines returns the stacks of every goroutine, the same text
+// the runtime prints when a program crashes
+func dumpGoroutines() string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// parseGoroutines splits a goroutine dump into its goroutines
+func parseGoroutines(dump string) []Goroutine {
+	var gs []Goroutine
+	for _, block := range strings.Split(strings.TrimSpace(dump), "\n\n") {
+		m := goroutineHeader.FindStringSubmatch(block)
+		if m == nil {
+			continue
+		}
+		id, _ := strconv.Atoi(m[1])
+		// The state can have extras tacked on, like "chan receive, 2 minutes"
+		state, _, _ := strings.Cut(m[2], ",")
+		gs = append(gs, Goroutine{ID: id, State: state, Stack: block})
+	}
+	return gs
+}
+
+// goroutineIDs returns the IDs of every running goroutine
+func goroutineIDs() map[int]bool {
+	ids := map[int]bool{}
+	for _, g := range parseGoroutines(dumpGoroutines()) {
+		ids[g.ID] = true
+	}
+	return ids
+}
+
+// leakedGoroutines returns the goroutines that are running now but
+// weren't in before. Goroutines that are just finishing up get until
+// grace to exit before they count as leaked.
+func leakedGoroutines(before map[int]bool, grace time.Duration) []Goroutine {
+	deadline := time.Now().Add(grace)
+	for {
+		var leaked []Goroutine
+		for _, g := range parseGoroutines(dumpGoroutines()) {
+			if !before[g.ID] {
+				leaked = append(leaked, g)
+			}
+		}
+		if len(leaked) == 0 || time.Now().After(deadline) {
+			sort.Slice(leaked, func(i, j int) bool { return leaked[i].ID < leaked[j].ID })
+			return leaked
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
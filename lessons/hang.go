@@ -0,0 +1,83 @@
+package lessons
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// funcName is the full name of a lesson's Run function, like
+// github.com/nicolasjhampton/hellogo/channels.channelRange
+func funcName(fn func()) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return ""
+	}
+	return f.Name()
+}
+
+// packagePrefix is the part of a function name that says which package
+// it's in, dot included
+func packagePrefix(name string) string {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return name
+	}
+	return name[:slash+1+dot+1]
+}
+
+// diagnoseHang goes through a goroutine dump taken when a lesson timed
+// out and adds a Note to every goroutine that's stuck inside the lesson's
+// package, saying which lesson function is blocked on what. Lessons lean
+// on helpers and closures (channelSelect's logger, goroutineMutexes'
+// increment), so anything in the package counts. Goroutines that were
+// already running before the lesson started, like ones an earlier
+// lesson abandoned, don't get a Note.
+func diagnoseHang(l Lesson, before map[int]bool, dump []Goroutine) []Goroutine {
+	prefix := packagePrefix(funcName(l.Run))
+	for i, g := range dump {
+		if before[g.ID] {
+			continue
+		}
+		frames := g.Frames()
+		for _, f := range frames {
+			if !strings.HasPrefix(f.Func, prefix) {
+				continue
+			}
+			name := strings.TrimPrefix(f.Func, prefix[:strings.LastIndex(prefix, "/")+1])
+			dump[i].Note = fmt.Sprintf("%s (%s:%d) is blocked on %s",
+				name, filepath.Base(f.File), f.Line, blockedOn(g.State, frames))
+			break
+		}
+	}
+	return dump
+}
+
+// blockedOn describes the operation a goroutine is waiting on
+func blockedOn(state string, frames []Frame) string {
+	switch state {
+	case "chan receive", "chan receive (nil chan)":
+		return "a channel receive"
+	case "chan send", "chan send (nil chan)":
+		return "a channel send"
+	case "select", "select (no cases)":
+		return "a select"
+	case "sleep":
+		return "time.Sleep"
+	}
+	// Mutexes and WaitGroups show up as the sync method the goroutine
+	// is parked in. Newer runtimes put it in the state, like
+	// sync.WaitGroup.Wait, older ones just say semacquire.
+	if strings.HasPrefix(state, "sync.") {
+		return state
+	}
+	for _, f := range frames {
+		if strings.HasPrefix(f.Func, "sync.(") {
+			return f.Func
+		}
+	}
+	return state
+}
@@ -0,0 +1,40 @@
+package lessons
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// Memory addresses change every run
+	addressPattern = regexp.MustCompile(`0x[0-9a-f]{6,}`)
+	// So do the times the logger prints
+	timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}`)
+)
+
+// Scrub replaces the parts of lesson output that change on every run,
+// addresses and timestamps, with placeholders
+func Scrub(out string) string {
+	out = addressPattern.ReplaceAllString(out, "0xADDR")
+	return timestampPattern.ReplaceAllString(out, "TIMESTAMP")
+}
+
+// Normalize rewrites a lesson's output so two runs of the same lesson
+// can be compared. It's scrubbed, and an Unordered lesson has its lines
+// sorted.
+func Normalize(l Lesson, out string) string {
+	out = Scrub(out)
+	if l.Unordered && out != "" {
+		// Without this, whichever line was printed last would be the
+		// only one missing its newline, and sorting would move it
+		if !strings.HasSuffix(out, "\n") {
+			out += "\n"
+		}
+		lines := strings.SplitAfter(out, "\n")
+		lines = lines[:len(lines)-1]
+		sort.Strings(lines)
+		out = strings.Join(lines, "")
+	}
+	return out
+}
@@ -0,0 +1,19 @@
+package lessons
+
+import "testing"
+
+func TestNormalizeUnordered(t *testing.T) {
+	l := Lesson{Unordered: true}
+	for _, out := range []string{"b\nc\na\n", "b\nc\na", "c\na\nb"} {
+		if got := Normalize(l, out); got != "a\nb\nc\n" {
+			t.Errorf("Normalize(%q) = %q, want %q", out, got, "a\nb\nc\n")
+		}
+	}
+	if got := Normalize(l, ""); got != "" {
+		t.Errorf("Normalize(%q) = %q, want it left empty", "", got)
+	}
+	// Ordered output is only scrubbed
+	if got := Normalize(Lesson{}, "b\na"); got != "b\na" {
+		t.Errorf("Normalize of an ordered lesson = %q, want %q", got, "b\na")
+	}
+}
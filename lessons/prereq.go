@@ -0,0 +1,141 @@
+package lessons
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Prerequisites returns the lessons l depends on directly. A required
+// concept stands for the first lesson in the book that teaches it.
+func Prerequisites(l Lesson) ([]Lesson, error) {
+	var ps []Lesson
+	for _, req := range l.Requires {
+		p, err := resolve(req)
+		if err != nil {
+			return nil, fmt.Errorf("%s requires %w", l.ID, err)
+		}
+		if p.ID != l.ID && !containsLesson(ps, p.ID) {
+			ps = append(ps, p)
+		}
+	}
+	return ps, nil
+}
+
+// resolve turns a Requires entry into the lesson it stands for
+func resolve(req string) (Lesson, error) {
+	if strings.Contains(req, "/") {
+		l, ok := Lookup(req)
+		if !ok {
+			return Lesson{}, fmt.Errorf("%q, which isn't a lesson", req)
+		}
+		return l, nil
+	}
+	for _, l := range All() {
+		for _, c := range l.Teaches {
+			if c == req {
+				return l, nil
+			}
+		}
+	}
+	return Lesson{}, fmt.Errorf("%q, which no lesson teaches", req)
+}
+
+// Path returns the lessons to work through to finish targets, with
+// every lesson after the ones it requires. Lessons that done says are
+// finished are left out, along with anything only they needed. Where
+// the order is up for grabs, lessons keep their book order.
+func Path(targets []Lesson, done func(id string) bool) ([]Lesson, error) {
+	// Walk back from the targets to find everything they need, and
+	// which of those lessons need which
+	needs := map[string][]string{}
+	var visit func(l Lesson) error
+	visit = func(l Lesson) error {
+		if _, ok := needs[l.ID]; ok || done(l.ID) {
+			return nil
+		}
+		ps, err := Prerequisites(l)
+		if err != nil {
+			return err
+		}
+		needs[l.ID] = nil
+		for _, p := range ps {
+			if done(p.ID) {
+				continue
+			}
+			needs[l.ID] = append(needs[l.ID], p.ID)
+			if err := visit(p); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, t := range targets {
+		if err := visit(t); err != nil {
+			return nil, err
+		}
+	}
+
+	// Then take them in book order, each time picking the earliest
+	// lesson whose prerequisites have all been taken
+	var pending []Lesson
+	for _, l := range All() {
+		if _, ok := needs[l.ID]; ok {
+			pending = append(pending, l)
+		}
+	}
+	taken := map[string]bool{}
+	var path []Lesson
+	for len(pending) > 0 {
+		next := -1
+		for i, l := range pending {
+			if allTaken(needs[l.ID], taken) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			var ids []string
+			for _, l := range pending {
+				ids = append(ids, l.ID)
+			}
+			sort.Strings(ids)
+			return nil, fmt.Errorf("lessons require each other in a loop: %s", strings.Join(ids, ", "))
+		}
+		l := pending[next]
+		taken[l.ID] = true
+		path = append(path, l)
+		pending = append(pending[:next], pending[next+1:]...)
+	}
+	return path, nil
+}
+
+// Missing returns everything l needs, directly or not, that done says
+// isn't finished yet, in the order to do it
+func Missing(l Lesson, done func(id string) bool) ([]Lesson, error) {
+	path, err := Path([]Lesson{l}, func(id string) bool {
+		return id != l.ID && done(id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return path[:len(path)-1], nil
+}
+
+func allTaken(ids []string, taken map[string]bool) bool {
+	for _, id := range ids {
+		if !taken[id] {
+			return false
+		}
+	}
+	return true
+}
+
+func containsLesson(ls []Lesson, id string) bool {
+	for _, l := range ls {
+		if l.ID == id {
+			return true
+		}
+	}
+	return false
+}
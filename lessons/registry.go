@@ -0,0 +1,146 @@
+// Package lessons is the central registry for every lesson in the book.
+//
+// Each chapter package registers its lessons from an init function, so
+// the main program only has to import a chapter for its lessons to show
+// up. Lessons get a stable ID like "channels/select" that the command
+// line uses to find them.
+package lessons
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A Lesson is one runnable example from a chapter
+type Lesson struct {
+	// ID is "<chapter>/<name>", and it shouldn't change once published,
+	// since people use it to pick lessons to run
+	ID      string
+	Title   string
+	Chapter string
+	// Order sorts lessons inside their chapter. If it's left at zero,
+	// Register uses the lesson's position in the list it was given
+	Order int
+	Run   func()
+	// Unordered is set on lessons whose lines come out in a different
+	// order from run to run, like goroutines racing to print. Their
+	// output is compared without caring about line order.
+	Unordered bool
+	// Panics is set on lessons that are supposed to panic. The lesson
+	// passes if it panics with a message containing this text.
+	Panics string
+	// FixedBy is the ID of a lesson that shows how to do this one
+	// without its data race. The race command checks that it runs clean.
+	FixedBy string
+	// Quiz turns the lesson into a "predict the output" question
+	Quiz *Quiz
+	// Teaches names the concepts this lesson introduces, like "pointer
+	// receivers", so other lessons can require them by name
+	Teaches []string
+	// Requires lists what a learner should have done before this
+	// lesson. Each entry is a lesson ID or a concept that some lesson
+	// Teaches.
+	Requires []string
+}
+
+// A Quiz asks the learner to predict what a lesson prints before they
+// get to see it. The right answer is whatever the lesson really prints,
+// so it can't go stale.
+type Quiz struct {
+	// Question is what to ask. It defaults to "What does this print?"
+	Question string
+	// Tail limits the question to the last lines of the output, for
+	// lessons whose surprise comes at the end of a lot of printing
+	Tail int
+	// Distractors are wrong answers that look right, written the way
+	// the lesson would print them. With none, the learner types their
+	// answer instead of picking one.
+	Distractors []string
+}
+
+// A Chapter groups lessons under the banner printed before them
+type Chapter struct {
+	Name    string // the ID prefix, like "channels"
+	Title   string // the banner text, like "CHANNELS"
+	Order   int    // chapters run from lowest to highest Order
+	Lessons []Lesson
+}
+
+var (
+	chapters = map[string]*Chapter{}
+	byID     = map[string]Lesson{}
+)
+
+// Register adds a chapter and its lessons to the registry. It's meant
+// to be called from a chapter package's init function, so mistakes like
+// duplicate IDs panic right away instead of turning up later as a
+// lesson that mysteriously never runs.
+func Register(c Chapter, ls ...Lesson) {
+	if c.Name == "" {
+		panic("lessons: chapter registered without a name")
+	}
+	if _, ok := chapters[c.Name]; ok {
+		panic(fmt.Sprintf("lessons: chapter %q registered twice", c.Name))
+	}
+	for i, l := range ls {
+		if !strings.HasPrefix(l.ID, c.Name+"/") {
+			panic(fmt.Sprintf("lessons: lesson %q must be in the %q chapter", l.ID, c.Name))
+		}
+		if _, ok := byID[l.ID]; ok {
+			panic(fmt.Sprintf("lessons: lesson %q registered twice", l.ID))
+		}
+		if l.Run == nil {
+			panic(fmt.Sprintf("lessons: lesson %q has no Run function", l.ID))
+		}
+		l.Chapter = c.Name
+		if l.Order == 0 {
+			l.Order = i + 1
+		}
+		byID[l.ID] = l
+		c.Lessons = append(c.Lessons, l)
+	}
+	sort.SliceStable(c.Lessons, func(i, j int) bool {
+		return c.Lessons[i].Order < c.Lessons[j].Order
+	})
+	chapters[c.Name] = &c
+}
+
+// Chapters returns every registered chapter in book order
+func Chapters() []Chapter {
+	cs := make([]Chapter, 0, len(chapters))
+	for _, c := range chapters {
+		cs = append(cs, *c)
+	}
+	sort.Slice(cs, func(i, j int) bool {
+		if cs[i].Order != cs[j].Order {
+			return cs[i].Order < cs[j].Order
+		}
+		return cs[i].Name < cs[j].Name
+	})
+	return cs
+}
+
+// LookupChapter finds a chapter by its name
+func LookupChapter(name string) (Chapter, bool) {
+	c, ok := chapters[name]
+	if !ok {
+		return Chapter{}, false
+	}
+	return *c, true
+}
+
+// Lookup finds a lesson by its ID
+func Lookup(id string) (Lesson, bool) {
+	l, ok := byID[id]
+	return l, ok
+}
+
+// All returns every registered lesson in book order
+func All() []Lesson {
+	var ls []Lesson
+	for _, c := range Chapters() {
+		ls = append(ls, c.Lessons...)
+	}
+	return ls
+}
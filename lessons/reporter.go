@@ -0,0 +1,209 @@
+package lessons
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// A Reporter is told what happens during a run, and decides how it's
+// shown. TextReporter prints what people have always seen, and
+// JSONReporter writes events for other programs to read.
+type Reporter interface {
+	StartRun(ls []Lesson)
+	StartChapter(c Chapter)
+	StartLesson(l Lesson)
+	// Output returns where a lesson's output should go. If it's nil,
+	// the lesson prints straight to stdout.
+	Output(l Lesson) io.Writer
+	EndLesson(res Result)
+	EndRun(results []Result)
+}
+
+// TextReporter prints chapter banners, the lesson source if
+// opts.ShowSource is set, and a Report and Separator after each lesson
+func TextReporter(w io.Writer, opts Options) Reporter {
+	return &textReporter{w: w, opts: opts}
+}
+
+type textReporter struct {
+	w    io.Writer
+	opts Options
+}
+
+func (r *textReporter) StartRun(ls []Lesson) {}
+
+func (r *textReporter) StartChapter(c Chapter) {
+	fmt.Fprintln(r.w, Banner(c.Title))
+}
+
+func (r *textReporter) StartLesson(l Lesson) {
+	if r.opts.ShowSource {
+		PrintSource(r.w, l)
+		fmt.Fprintln(r.w, "Output:")
+	}
+}
+
+func (r *textReporter) Output(l Lesson) io.Writer { return nil }
+
+func (r *textReporter) EndLesson(res Result) {
+	Report(r.w, res)
+	fmt.Fprintln(r.w, Separator)
+}
+
+func (r *textReporter) EndRun(results []Result) {}
+
+// An Event is one line written by JSONReporter. Event says what kind
+// it is, and only the fields that go with that kind are filled in:
+//
+//	run-start      Lessons
+//	chapter-start  Chapter, Title
+//	lesson-start   Chapter, Lesson, Title
+//	output         Lesson, Line
+//	panic          Lesson, Message, Stack, Expected
+//	lesson-end     Lesson, Status, DurationMS, Error, Leaks
+//	run-end        Passed, Failed, Total
+type Event struct {
+	Event   string    `json:"event"`
+	Time    time.Time `json:"time"`
+	Lessons []string  `json:"lessons,omitempty"`
+	Chapter string    `json:"chapter,omitempty"`
+	Lesson  string    `json:"lesson,omitempty"`
+	Title   string    `json:"title,omitempty"`
+	// Line is a pointer so an empty line still shows up as "", and the
+	// same goes for the fields below that can be false or zero
+	Line       *string  `json:"line,omitempty"`
+	Message    string   `json:"message,omitempty"`
+	Stack      string   `json:"stack,omitempty"`
+	Expected   *bool    `json:"expected,omitempty"`
+	Status     string   `json:"status,omitempty"`
+	DurationMS *float64 `json:"duration_ms,omitempty"`
+	Error      string   `json:"error,omitempty"`
+	Leaks      *int     `json:"leaks,omitempty"`
+	Passed     *int     `json:"passed,omitempty"`
+	Failed     *int     `json:"failed,omitempty"`
+	Total      *int     `json:"total,omitempty"`
+}
+
+// JSONReporter writes each step of a run to w as an Event, one JSON
+// object per line. Lesson output is captured and written a line at a
+// time, stamped with when it was printed.
+func JSONReporter(w io.Writer) Reporter {
+	return &jsonReporter{enc: json.NewEncoder(w)}
+}
+
+type jsonReporter struct {
+	// mu keeps events whole, since output events are written from the
+	// goroutine copying the lesson's output
+	mu  sync.Mutex
+	enc *json.Encoder
+	// out is the current lesson's output, kept to flush any last line
+	// that didn't end in a newline
+	out *eventLines
+}
+
+func (r *jsonReporter) emit(e Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	r.enc.Encode(e)
+}
+
+func (r *jsonReporter) StartRun(ls []Lesson) {
+	ids := make([]string, len(ls))
+	for i, l := range ls {
+		ids[i] = l.ID
+	}
+	r.emit(Event{Event: "run-start", Lessons: ids})
+}
+
+func (r *jsonReporter) StartChapter(c Chapter) {
+	r.emit(Event{Event: "chapter-start", Chapter: c.Name, Title: c.Title})
+}
+
+func (r *jsonReporter) StartLesson(l Lesson) {
+	r.emit(Event{Event: "lesson-start", Chapter: l.Chapter, Lesson: l.ID, Title: l.Title})
+}
+
+func (r *jsonReporter) Output(l Lesson) io.Writer {
+	r.out = &eventLines{r: r, lesson: l.ID}
+	return r.out
+}
+
+func (r *jsonReporter) EndLesson(res Result) {
+	if r.out != nil {
+		r.out.flush()
+		r.out = nil
+	}
+	if res.Panic != nil {
+		r.emit(Event{
+			Event:    "panic",
+			Lesson:   res.Lesson.ID,
+			Message:  fmt.Sprint(res.Panic),
+			Stack:    res.Stack,
+			Expected: ptr(res.Status == Passed),
+		})
+	}
+	e := Event{
+		Event:      "lesson-end",
+		Lesson:     res.Lesson.ID,
+		Status:     res.Status.String(),
+		DurationMS: ptr(float64(res.Duration) / float64(time.Millisecond)),
+		Leaks:      ptr(len(res.Leaks)),
+	}
+	if res.Err != nil {
+		e.Error = res.Err.Error()
+	}
+	r.emit(e)
+}
+
+func (r *jsonReporter) EndRun(results []Result) {
+	var passed, failed int
+	for _, res := range results {
+		if res.Status == Passed {
+			passed++
+		} else {
+			failed++
+		}
+	}
+	r.emit(Event{Event: "run-end", Passed: &passed, Failed: &failed, Total: ptr(len(results))})
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// eventLines turns what a lesson prints into output events, one per
+// line. A line without a newline yet is held until the next write, or
+// until flush at the end of the lesson.
+type eventLines struct {
+	r      *jsonReporter
+	lesson string
+	buf    []byte
+}
+
+func (el *eventLines) Write(p []byte) (int, error) {
+	el.buf = append(el.buf, p...)
+	for {
+		i := bytes.IndexByte(el.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := string(el.buf[:i])
+		el.r.emit(Event{Event: "output", Lesson: el.lesson, Line: &line})
+		el.buf = el.buf[i+1:]
+	}
+}
+
+func (el *eventLines) flush() {
+	if len(el.buf) > 0 {
+		line := string(el.buf)
+		el.r.emit(Event{Event: "output", Lesson: el.lesson, Line: &line})
+		el.buf = nil
+	}
+}
@@ -0,0 +1,157 @@
+package lessons
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// Status is how a lesson run turned out
+type Status int
+
+const (
+	Passed Status = iota
+	Failed
+	// TimedOut lessons were still running when Options.Timeout ran out
+	TimedOut
+)
+
+func (s Status) String() string {
+	switch s {
+	case Passed:
+		return "pass"
+	case Failed:
+		return "fail"
+	case TimedOut:
+		return "timeout"
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// Options control how the runner treats each lesson
+type Options struct {
+	// Timeout is how long a lesson gets to run before the runner gives
+	// up on it and moves on. Zero means wait forever.
+	Timeout time.Duration
+	// LeakGrace is how long a lesson's goroutines get to finish after
+	// the lesson returns before they're reported as leaked
+	LeakGrace time.Duration
+	// Strict fails lessons that leak goroutines instead of just warning
+	// about them
+	Strict bool
+	// ShowSource prints each lesson's code and the comments above it
+	// before running it, so a run reads like the tutorial it came from
+	ShowSource bool
+}
+
+// DefaultOptions are used by RunChapter and the XxxLessons functions
+var DefaultOptions = Options{
+	Timeout:   5 * time.Second,
+	LeakGrace: 200 * time.Millisecond,
+}
+
+// A Result is what happened when a lesson ran
+type Result struct {
+	Lesson Lesson
+	Status Status
+	// Panic is the value the lesson panicked with, and Stack is where it
+	// panicked from. Both are empty if the lesson returned normally.
+	Panic any
+	Stack string
+	// Leaks are the goroutines the lesson started and left running
+	Leaks []Goroutine
+	// Hang is every goroutine that was running when the lesson timed
+	// out, with Notes on the ones stuck inside the lesson
+	Hang []Goroutine
+	// Err explains why a lesson failed
+	Err      error
+	Duration time.Duration
+}
+
+// RunLesson runs a single lesson with a recover in place, so a panicking
+// lesson fails on its own instead of taking every lesson after it down
+// too. A lesson that sets Panics passes only if it panics with that
+// message.
+//
+// The goroutines running before and after the lesson are compared too,
+// and any the lesson left behind end up in the result's Leaks.
+//
+// The lesson runs in its own goroutine so the runner can stop waiting
+// for it after opts.Timeout. A lesson that deadlocks would otherwise
+// crash the program with "all goroutines are asleep", or hang forever
+// if any other goroutine is still alive. A timed out lesson is
+// abandoned, not stopped, since Go has no way to kill a goroutine.
+func RunLesson(l Lesson, opts Options) Result {
+	res := Result{Lesson: l}
+	before := goroutineIDs()
+	start := time.Now()
+
+	type outcome struct {
+		panic any
+		stack string
+	}
+	// Buffered so an abandoned lesson can still finish sending
+	finished := make(chan outcome, 1)
+	go func() {
+		p, stack := call(l.Run)
+		finished <- outcome{p, stack}
+	}()
+	var timeout <-chan time.Time
+	if opts.Timeout > 0 {
+		timer := time.NewTimer(opts.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case o := <-finished:
+		res.Duration = time.Since(start)
+		res.Panic, res.Stack = o.panic, o.stack
+	case <-timeout:
+		res.Duration = time.Since(start)
+		res.Status = TimedOut
+		res.Err = fmt.Errorf("still running after %v", opts.Timeout)
+		// The stuck goroutines are the whole story here, so they're
+		// reported in the hang instead of as leaks
+		res.Hang = diagnoseHang(l, before, parseGoroutines(dumpGoroutines()))
+		return res
+	}
+	res.Leaks = leakedGoroutines(before, opts.LeakGrace)
+
+	res.Err = checkPanic(l, res.Panic)
+	if res.Err == nil && opts.Strict && len(res.Leaks) > 0 {
+		res.Err = fmt.Errorf("left %d goroutine(s) running", len(res.Leaks))
+	}
+	if res.Err != nil {
+		res.Status = Failed
+	}
+	return res
+}
+
+// call runs fn and hands back anything it panicked with. The recover has
+// to be in a deferred function in the same goroutine as the panic, so
+// this is as far out as it can go.
+func call(fn func()) (p any, stack string) {
+	defer func() {
+		if p = recover(); p != nil {
+			stack = string(debug.Stack())
+		}
+	}()
+	fn()
+	return nil, ""
+}
+
+func checkPanic(l Lesson, p any) error {
+	switch {
+	case p == nil && l.Panics == "":
+		return nil
+	case p == nil:
+		return fmt.Errorf("expected a panic with %q, but the lesson returned normally", l.Panics)
+	case l.Panics == "":
+		return fmt.Errorf("panic: %v", p)
+	case !strings.Contains(fmt.Sprint(p), l.Panics):
+		return fmt.Errorf("expected a panic with %q, got: %v", l.Panics, p)
+	}
+	return nil
+}
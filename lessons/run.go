@@ -0,0 +1,129 @@
+package lessons
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Separator is printed after every lesson
+const Separator = "------------------------------------------------------------------"
+
+// Banner is the line printed before a chapter's lessons, like
+// ////////////////////////////*CHANNELS*////////////////////////////
+func Banner(title string) string {
+	bar := strings.Repeat("/", 28)
+	return bar + "*" + title + "*" + bar
+}
+
+// RunChapter prints the chapter banner and runs each of its lessons in
+// order with DefaultOptions, the same way every chapter's XxxLessons
+// function used to
+func RunChapter(name string) []Result {
+	c, ok := LookupChapter(name)
+	if !ok {
+		panic(fmt.Sprintf("lessons: no chapter named %q", name))
+	}
+	return Run(c.Lessons, DefaultOptions)
+}
+
+// Run runs a selection of lessons, printing a chapter banner each time
+// the selection moves into a new chapter
+func Run(ls []Lesson, opts Options) []Result {
+	return RunWith(ls, opts, TextReporter(os.Stdout, opts))
+}
+
+// RunWith runs a selection of lessons, telling rep about each step
+func RunWith(ls []Lesson, opts Options, rep Reporter) []Result {
+	rep.StartRun(ls)
+	chapter := ""
+	var results []Result
+	for _, l := range ls {
+		if l.Chapter != chapter {
+			chapter = l.Chapter
+			c, _ := LookupChapter(chapter)
+			rep.StartChapter(c)
+		}
+		rep.StartLesson(l)
+		var res Result
+		if w := rep.Output(l); w != nil {
+			err := Capture(w, func() {
+				res = RunLesson(l, opts)
+			})
+			if err != nil && res.Err == nil {
+				res.Status, res.Err = Failed, fmt.Errorf("capturing output: %w", err)
+			}
+		} else {
+			res = RunLesson(l, opts)
+		}
+		rep.EndLesson(res)
+		results = append(results, res)
+	}
+	rep.EndRun(results)
+	return results
+}
+
+// PrintSource prints a lesson's title, where it lives, and its code
+func PrintSource(w io.Writer, l Lesson) {
+	fmt.Fprintf(w, "=== %s: %s\n", l.ID, l.Title)
+	code, err := Source(l)
+	if err != nil {
+		fmt.Fprintf(w, "(source not available: %v)\n\n", err)
+		return
+	}
+	fmt.Fprintf(w, "%s:%d\n\n%s\n\n", RepoPath(code.File), code.Line, code.Text)
+}
+
+// Report prints anything worth knowing about a result besides the
+// lesson's own output, like a panic it recovered from or goroutines it
+// leaked
+func Report(w io.Writer, res Result) {
+	switch {
+	case res.Status == Passed && res.Panic != nil:
+		fmt.Fprintf(w, "(recovered the expected panic: %v)\n", res.Panic)
+	case res.Status == Failed:
+		fmt.Fprintf(w, "FAIL %s: %v\n", res.Lesson.ID, res.Err)
+		if res.Stack != "" {
+			fmt.Fprint(w, res.Stack)
+		}
+	case res.Status == TimedOut:
+		fmt.Fprintf(w, "TIMEOUT %s: %v\n", res.Lesson.ID, res.Err)
+		for _, g := range res.Hang {
+			if g.Note != "" {
+				fmt.Fprintf(w, "  %s\n", g.Note)
+			}
+		}
+		fmt.Fprintln(w, "\nFull goroutine dump:")
+		for _, g := range res.Hang {
+			if g.Note != "" {
+				fmt.Fprintf(w, "\n# %s\n", g.Note)
+			}
+			fmt.Fprintf(w, "\n%s\n", g.Stack)
+		}
+	}
+	if len(res.Leaks) > 0 {
+		// In strict mode the FAIL line above already said why
+		if res.Status == Passed {
+			fmt.Fprintf(w, "WARNING %s left %d goroutine(s) running\n", res.Lesson.ID, len(res.Leaks))
+		}
+		for _, g := range res.Leaks {
+			fmt.Fprintf(w, "\n%s\n", g.Stack)
+		}
+	}
+}
+
+// Summarize returns an error describing the failed results, or nil if
+// every lesson passed
+func Summarize(results []Result) error {
+	var failed []string
+	for _, res := range results {
+		if res.Status != Passed {
+			failed = append(failed, res.Lesson.ID)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d lessons failed: %s", len(failed), len(results), strings.Join(failed, ", "))
+}
@@ -0,0 +1,157 @@
+package lessons
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// modulePath is what file paths start with in binaries built with
+// -trimpath, instead of the directory the module was built in
+const modulePath = "github.com/nicolasjhampton/hellogo/"
+
+// Code is a lesson's function as it appears in its source file
+type Code struct {
+	File string
+	Line int // the line the comment above the function starts on
+	// Doc is the comment above the function with the // markers taken
+	// off, the prose that goes with the code
+	Doc string
+	// Func is the function itself, from the func keyword to the closing
+	// brace, comments inside it included
+	Func string
+	// Text is the comment above the function and the function, exactly
+	// as they are in the file
+	Text string
+}
+
+// repoRoot is the directory the module was built in, worked out from
+// where this file was when it was compiled
+var repoRoot = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(filepath.Dir(file))
+}()
+
+// RepoRoot returns the directory the module was built in, or the
+// working directory if that's gone, which is where the go tool has to
+// run to build the lessons again
+func RepoRoot() string {
+	if _, err := os.Stat(filepath.Join(repoRoot, "go.mod")); err == nil {
+		return repoRoot
+	}
+	return "."
+}
+
+// RepoPath turns a source file path from the binary into a path
+// relative to the root of the repo, like channels/channels.go
+func RepoPath(file string) string {
+	if strings.HasPrefix(file, modulePath) {
+		return strings.TrimPrefix(file, modulePath)
+	}
+	rel, err := filepath.Rel(repoRoot, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return file
+	}
+	return filepath.ToSlash(rel)
+}
+
+var (
+	parsedMu sync.Mutex
+	parsed   = map[string]*parsedFile{}
+)
+
+type parsedFile struct {
+	fset *token.FileSet
+	file *ast.File
+	src  []byte
+}
+
+// Source finds the source of a lesson's Run function. Go keeps the file
+// and line of every function in the binary, so this works as long as the
+// source is still where it was built, or the binary is run from the root
+// of the repo.
+func Source(l Lesson) (Code, error) {
+	file, line, err := funcLine(l.Run)
+	if err != nil {
+		return Code{}, fmt.Errorf("%s: %w", l.ID, err)
+	}
+	pf, err := parseFile(file)
+	if err != nil {
+		return Code{}, fmt.Errorf("%s: %w", l.ID, err)
+	}
+
+	for _, decl := range pf.file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		start, end := pf.fset.Position(fd.Pos()), pf.fset.Position(fd.End())
+		if line < start.Line || line > end.Line {
+			continue
+		}
+		code := Code{
+			File: pf.fset.Position(fd.Pos()).Filename,
+			Line: start.Line,
+			Func: string(pf.src[start.Offset:end.Offset]),
+		}
+		from := start.Offset
+		if fd.Doc != nil {
+			doc := pf.fset.Position(fd.Doc.Pos())
+			from = doc.Offset
+			code.Line = doc.Line
+			code.Doc = fd.Doc.Text()
+		}
+		code.Text = string(pf.src[from:end.Offset])
+		return code, nil
+	}
+	return Code{}, fmt.Errorf("%s: no function at %s:%d", l.ID, file, line)
+}
+
+// funcLine returns the file and line a function starts on
+func funcLine(f any) (file string, line int, err error) {
+	v := reflect.ValueOf(f)
+	if v.Kind() != reflect.Func {
+		return "", 0, fmt.Errorf("%T isn't a function", f)
+	}
+	fn := runtime.FuncForPC(v.Pointer())
+	if fn == nil {
+		return "", 0, errors.New("can't find the function")
+	}
+	file, line = fn.FileLine(fn.Entry())
+	return file, line, nil
+}
+
+// parseFile parses a source file once and keeps it around, since most
+// files hold a whole chapter's worth of lessons
+func parseFile(file string) (*parsedFile, error) {
+	parsedMu.Lock()
+	defer parsedMu.Unlock()
+	if pf, ok := parsed[file]; ok {
+		return pf, nil
+	}
+
+	src, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) && strings.HasPrefix(file, modulePath) {
+		// Built with -trimpath, so look for it from the repo root
+		src, err = os.ReadFile(filepath.FromSlash(strings.TrimPrefix(file, modulePath)))
+	}
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	pf := &parsedFile{fset: fset, file: f, src: src}
+	parsed[file] = pf
+	return pf, nil
+}
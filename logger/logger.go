@@ -0,0 +1,226 @@
+// Package logger is the logger from the channels chapter, grown up
+// enough to use for real. Logging a message just puts it on a buffered
+// channel, and a goroutine of the logger's own writes it out, so callers
+// never wait on a slow writer unless the buffer fills up. What happens
+// then is up to the Options the logger was made with: it can wait, give
+// up waiting after a while, or drop entries, counting them in Stats.
+//
+// Unlike the one in the lesson, this logger can be shut down. Close
+// writes out everything still in the buffer before it returns, and
+// Flush waits for everything logged so far to be written while the
+// logger keeps going.
+//
+// Where entries end up is up to the Sinks the logger is given. Every
+// entry goes to every sink, unless the sink is wrapped in AtLeast to
+// leave out the less serious ones:
+//
+//	f, err := logger.OpenRotating("app.log", logger.Rotation{MaxSize: 10 << 20, MaxBackups: 5})
+//	...
+//	log := logger.New(logger.Console(), logger.AtLeast(logger.Warn, logger.JSONLines(f)))
+//	defer f.Close()
+//	defer log.Close()
+//	log.Info("App is starting")
+package logger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Severity is how serious an entry is
+type Severity int
+
+const (
+	Info Severity = iota
+	Warn
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "INFO"
+	case Warn:
+		return "WARNING"
+	case Error:
+		return "ERROR"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// MarshalText makes severities show up by name in JSON
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// An Entry is one logged message
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Severity Severity  `json:"severity"`
+	Message  string    `json:"message"`
+}
+
+// A Logger writes entries in the background. It's safe to use from any
+// number of goroutines.
+type Logger struct {
+	sinks   []Sink
+	opts    Options
+	stats   counters
+	entries chan Entry
+	flushes chan chan struct{}
+	done    chan struct{}
+
+	// mu is held for reading while sending an entry and for writing
+	// while closing, so nothing is ever sent on a closed channel
+	mu     sync.RWMutex
+	closed bool
+
+	// err is the first error from any sink, which Close returns. Only
+	// the logger's goroutine sets it, before done is closed.
+	err error
+}
+
+// New starts a Logger that writes to sinks with DefaultOptions. The
+// sinks are only ever used from the logger's goroutine, so they don't
+// need to be safe for concurrent use.
+func New(sinks ...Sink) *Logger {
+	return NewWith(DefaultOptions, sinks...)
+}
+
+// NewWith starts a Logger that writes to sinks with opts
+func NewWith(opts Options, sinks ...Sink) *Logger {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = DefaultOptions.BufferSize
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultOptions.Timeout
+	}
+	if opts.SampleRate <= 0 {
+		opts.SampleRate = DefaultOptions.SampleRate
+	}
+	l := &Logger{
+		sinks:   sinks,
+		opts:    opts,
+		entries: make(chan Entry, opts.BufferSize),
+		flushes: make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// Info logs a message at Info severity. The arguments are handled like
+// fmt.Sprintf's.
+func (l *Logger) Info(format string, args ...any) {
+	l.log(Info, format, args...)
+}
+
+// Warn logs a message at Warn severity
+func (l *Logger) Warn(format string, args ...any) {
+	l.log(Warn, format, args...)
+}
+
+// Error logs a message at Error severity
+func (l *Logger) Error(format string, args ...any) {
+	l.log(Error, format, args...)
+}
+
+// log queues an entry. Entries logged after Close are dropped, since
+// there's nothing left to write them.
+func (l *Logger) log(s Severity, format string, args ...any) {
+	e := Entry{Time: time.Now(), Severity: s, Message: fmt.Sprintf(format, args...)}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		return
+	}
+	l.send(e)
+}
+
+// Flush waits until every entry logged before it was called has been
+// written, or until ctx is done
+func (l *Logger) Flush(ctx context.Context) error {
+	ack := make(chan struct{})
+	select {
+	case l.flushes <- ack:
+	case <-l.done:
+		// Closed, so everything has been written already
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops the logger once it's written every entry still waiting in
+// the buffer. It returns the first error writing any entry, and it's
+// fine to call more than once.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		// Closing the channel is what tells run to finish up. It still
+		// receives everything already in the buffer first.
+		close(l.entries)
+	}
+	l.mu.Unlock()
+	<-l.done
+	return l.err
+}
+
+func (l *Logger) run() {
+	defer close(l.done)
+	for {
+		// The lesson's version used a break in here to stop, but break
+		// only leaves the select. Returning leaves the loop too.
+		select {
+		case e, ok := <-l.entries:
+			if !ok {
+				return
+			}
+			l.write(e)
+		case ack := <-l.flushes:
+			// Everything logged before Flush was called is already in
+			// the buffer, so writing what's there right now is enough.
+			// Waiting for the buffer to be empty instead could take
+			// forever if other goroutines keep logging.
+			l.drain(len(l.entries))
+			close(ack)
+		}
+	}
+}
+
+// drain writes up to n entries from the buffer. There can be fewer than
+// n by the time it gets to them, if DropOldest or Sample pushed some
+// out, so it never waits for more.
+func (l *Logger) drain(n int) {
+	for ; n > 0; n-- {
+		select {
+		case e, ok := <-l.entries:
+			if !ok {
+				return
+			}
+			l.write(e)
+		default:
+			return
+		}
+	}
+}
+
+// write hands e to every sink. A sink that fails doesn't keep the
+// entry from the others.
+func (l *Logger) write(e Entry) {
+	l.stats.written.Add(1)
+	for _, s := range l.sinks {
+		if err := s.Write(e); err != nil && l.err == nil {
+			l.err = err
+		}
+	}
+}
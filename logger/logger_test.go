@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a Sink that keeps every entry it's given
+type recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+	// gate, if it's set, holds up every write until it's closed
+	gate chan struct{}
+}
+
+func (r *recorder) Write(e Entry) error {
+	if r.gate != nil {
+		<-r.gate
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e)
+	return nil
+}
+
+func (r *recorder) messages() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ms []string
+	for _, e := range r.entries {
+		ms = append(ms, e.Message)
+	}
+	return ms
+}
+
+func TestCloseWritesBufferedEntries(t *testing.T) {
+	r := &recorder{gate: make(chan struct{})}
+	l := New(r)
+	for i := range 10 {
+		l.Info("entry %d", i)
+	}
+	// Everything is still waiting on the gate, so Close has to write it
+	close(r.gate)
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	got := r.messages()
+	if len(got) != 10 {
+		t.Fatalf("wrote %d entries, want 10: %q", len(got), got)
+	}
+	for i, m := range got {
+		if want := fmt.Sprintf("entry %d", i); m != want {
+			t.Errorf("entry %d is %q, want %q", i, m, want)
+		}
+	}
+
+	// Logging after Close is dropped rather than panicking
+	l.Info("too late")
+	if err := l.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if n := len(r.messages()); n != 10 {
+		t.Errorf("wrote %d entries after Close, want 10", n)
+	}
+}
+
+func TestFlushWaitsForEntries(t *testing.T) {
+	r := &recorder{}
+	l := New(r)
+	defer l.Close()
+	l.Info("one")
+	l.Warn("two")
+	if err := l.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if got := r.messages(); len(got) != 2 {
+		t.Errorf("Flush returned with %d entries written, want 2", len(got))
+	}
+}
+
+func TestFlushGivesUpWhenContextIsDone(t *testing.T) {
+	r := &recorder{gate: make(chan struct{})}
+	l := New(r)
+	l.Info("stuck")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	errc := make(chan error, 1)
+	go func() { errc <- l.Flush(ctx) }()
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Flush = %v, want %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Flush didn't return after its context was done")
+	}
+
+	close(r.gate)
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := r.messages(); len(got) != 1 {
+		t.Errorf("wrote %q, want the one entry", got)
+	}
+}
+
+func TestConcurrentLogging(t *testing.T) {
+	const goroutines, each = 8, 200
+	r := &recorder{}
+	l := New(r)
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range each {
+				l.Info("%d %d", g, i)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Entries from different goroutines can be interleaved any which
+	// way, but each goroutine's own come out in the order it logged them
+	got := r.messages()
+	if len(got) != goroutines*each {
+		t.Fatalf("wrote %d entries, want %d", len(got), goroutines*each)
+	}
+	next := make([]int, goroutines)
+	for _, m := range got {
+		var g, i int
+		if _, err := fmt.Sscanf(m, "%d %d", &g, &i); err != nil {
+			t.Fatalf("bad entry %q: %v", m, err)
+		}
+		if i != next[g] {
+			t.Fatalf("goroutine %d's entry %d came out when %d was next", g, i, next[g])
+		}
+		next[g]++
+	}
+}
+
+func TestSinkErrors(t *testing.T) {
+	errBroken := errors.New("broken")
+	r := &recorder{}
+	broken := SinkFunc(func(Entry) error { return errBroken })
+	l := New(broken, r)
+	l.Error("still written")
+	// The first error comes back from Close, and the other sink still
+	// gets the entry
+	if err := l.Close(); !errors.Is(err, errBroken) {
+		t.Errorf("Close = %v, want %v", err, errBroken)
+	}
+	if got := r.messages(); len(got) != 1 {
+		t.Errorf("working sink got %q, want the entry", got)
+	}
+}
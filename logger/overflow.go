@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// A Policy is what logging does when the buffer is full because the
+// sinks can't keep up
+type Policy int
+
+const (
+	// Block waits for room, like the channel in the lesson does
+	Block Policy = iota
+	// BlockTimeout waits for room for up to Options.Timeout, then drops
+	// the entry
+	BlockTimeout
+	// DropNewest drops the entry being logged
+	DropNewest
+	// DropOldest drops the entry that's been waiting longest, to make
+	// room for the one being logged
+	DropOldest
+	// Sample keeps one in every Options.SampleRate entries logged while
+	// the buffer is full, making room for it like DropOldest does, and
+	// drops the rest
+	Sample
+)
+
+func (p Policy) String() string {
+	switch p {
+	case Block:
+		return "block"
+	case BlockTimeout:
+		return "block-timeout"
+	case DropNewest:
+		return "drop-newest"
+	case DropOldest:
+		return "drop-oldest"
+	case Sample:
+		return "sample"
+	}
+	return fmt.Sprintf("Policy(%d)", int(p))
+}
+
+// Options control how much a Logger buffers and what happens when the
+// buffer fills up. Anything left at zero is taken from DefaultOptions.
+type Options struct {
+	// BufferSize is how many entries can be waiting to be written
+	BufferSize int
+	Overflow   Policy
+	// Timeout is how long BlockTimeout waits
+	Timeout time.Duration
+	// SampleRate is how often Sample keeps an entry
+	SampleRate int
+}
+
+// DefaultOptions are used by New. They block once 50 entries are
+// waiting, the same as in the lesson.
+var DefaultOptions = Options{
+	BufferSize: 50,
+	Overflow:   Block,
+	Timeout:    100 * time.Millisecond,
+	SampleRate: 10,
+}
+
+// Stats counts what the overflow policy has had to do. Once the logger
+// is closed, Written and Dropped add up to every entry logged before
+// Close.
+type Stats struct {
+	// Written is how many entries were handed to the sinks
+	Written uint64
+	// Dropped is how many entries were never written because the
+	// buffer was full
+	Dropped uint64
+	// Blocked is how many entries had to wait for room, whether or not
+	// they got it in the end
+	Blocked uint64
+}
+
+// counters are the live version of Stats
+type counters struct {
+	written, dropped, blocked, sampled atomic.Uint64
+}
+
+// Stats returns the logger's counters so far
+func (l *Logger) Stats() Stats {
+	return Stats{
+		Written: l.stats.written.Load(),
+		Dropped: l.stats.dropped.Load(),
+		Blocked: l.stats.blocked.Load(),
+	}
+}
+
+// send queues e according to the overflow policy. The caller holds
+// l.mu for reading, so entries can't be closed underneath it.
+func (l *Logger) send(e Entry) {
+	select {
+	case l.entries <- e:
+		return
+	default:
+	}
+
+	switch l.opts.Overflow {
+	case BlockTimeout:
+		l.stats.blocked.Add(1)
+		t := time.NewTimer(l.opts.Timeout)
+		defer t.Stop()
+		select {
+		case l.entries <- e:
+		case <-t.C:
+			l.stats.dropped.Add(1)
+		}
+	case DropNewest:
+		l.stats.dropped.Add(1)
+	case DropOldest:
+		l.pushOut(e)
+	case Sample:
+		if l.stats.sampled.Add(1)%uint64(l.opts.SampleRate) != 0 {
+			l.stats.dropped.Add(1)
+			return
+		}
+		l.pushOut(e)
+	default:
+		l.stats.blocked.Add(1)
+		l.entries <- e
+	}
+}
+
+// pushOut makes room for e by taking the oldest entry off the buffer.
+// Other goroutines are doing the same thing, and the logger's goroutine
+// is taking entries off too, so it keeps trying until e fits.
+func (l *Logger) pushOut(e Entry) {
+	for {
+		select {
+		case l.entries <- e:
+			return
+		default:
+		}
+		select {
+		case <-l.entries:
+			l.stats.dropped.Add(1)
+		default:
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOverflowPolicies(t *testing.T) {
+	const goroutines, each = 4, 100
+	for _, p := range []Policy{Block, BlockTimeout, DropNewest, DropOldest, Sample} {
+		t.Run(p.String(), func(t *testing.T) {
+			r := &recorder{}
+			// A slow sink and a tiny buffer, so the buffer is full
+			// nearly the whole time
+			slow := SinkFunc(func(e Entry) error {
+				time.Sleep(20 * time.Microsecond)
+				return r.Write(e)
+			})
+			l := NewWith(Options{BufferSize: 2, Overflow: p, Timeout: time.Millisecond, SampleRate: 3}, slow)
+			var wg sync.WaitGroup
+			for range goroutines {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := range each {
+						l.Info("entry %d", i)
+					}
+				}()
+			}
+			wg.Wait()
+			if err := l.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			s := l.Stats()
+			if s.Written+s.Dropped != goroutines*each {
+				t.Errorf("written %d + dropped %d = %d, want %d", s.Written, s.Dropped, s.Written+s.Dropped, goroutines*each)
+			}
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if uint64(len(r.entries)) != s.Written {
+				t.Errorf("sink got %d entries, Stats says %d were written", len(r.entries), s.Written)
+			}
+			for _, e := range r.entries {
+				if e == (Entry{}) {
+					t.Fatal("sink got a zero Entry")
+				}
+			}
+
+			switch p {
+			case Block:
+				if s.Dropped != 0 {
+					t.Errorf("Block dropped %d entries", s.Dropped)
+				}
+				if s.Blocked == 0 {
+					t.Error("Block never blocked, so the buffer never filled up")
+				}
+			case DropNewest, DropOldest, Sample:
+				if s.Blocked != 0 {
+					t.Errorf("%v blocked %d times", p, s.Blocked)
+				}
+				if s.Dropped == 0 {
+					t.Errorf("%v never dropped, so the buffer never filled up", p)
+				}
+			}
+		})
+	}
+}
+
+func TestDropOldestKeepsNewest(t *testing.T) {
+	r := &recorder{gate: make(chan struct{})}
+	l := NewWith(Options{BufferSize: 2, Overflow: DropOldest}, r)
+	for _, m := range []string{"one", "two", "three", "four", "five"} {
+		l.Info("%s", m)
+	}
+	close(r.gate)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// The logger's goroutine may have taken "one" off the buffer before
+	// it got stuck on the gate, but whatever it kept last is the newest
+	got := r.messages()
+	if n := len(got); n < 2 || got[n-2] != "four" || got[n-1] != "five" {
+		t.Errorf("wrote %q, want it to end with four and five", got)
+	}
+}
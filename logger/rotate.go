@@ -0,0 +1,229 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatedLayout is the time added to the names of rotated files. It
+// goes down to the nanosecond, since a small MaxSize can rotate a file
+// more than once a millisecond.
+const rotatedLayout = "20060102-150405.000000000"
+
+// rename is os.Rename, swapped out by the tests to make rotating fail
+var rename = os.Rename
+
+// Rotation says when a RotatingFile starts over in a fresh file. Zero
+// for any of them means no limit.
+type Rotation struct {
+	// MaxSize is how many bytes a file can hold before it's rotated
+	MaxSize int64
+	// MaxAge is how long a file is written to before it's rotated
+	MaxAge time.Duration
+	// MaxBackups is how many rotated files are kept around. The oldest
+	// are deleted past that.
+	MaxBackups int
+}
+
+// A RotatingFile is a log file that moves itself out of the way when it
+// gets too big or too old. The old file is renamed with the time it was
+// rotated, like app.log.20261017-153000.000000000, and writing carries
+// on in a new app.log. Give it to Text or JSONLines to log to it.
+type RotatingFile struct {
+	path string
+	r    Rotation
+
+	mu     sync.Mutex
+	f      *os.File
+	size   int64
+	opened time.Time
+	closed bool
+}
+
+// OpenRotating opens the log file at path, adding to it if it's already
+// there. Its age is counted from when it's opened.
+func OpenRotating(path string, r Rotation) (*RotatingFile, error) {
+	rf := &RotatingFile{path: path, r: r}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *RotatingFile) open() error {
+	f, err := os.OpenFile(rf.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	rf.f, rf.size, rf.opened = f, info.Size(), time.Now()
+	return nil
+}
+
+// Write writes p to the current file, rotating first if p would take it
+// past MaxSize or it's older than MaxAge. A single write is never split
+// across files, so an entry bigger than MaxSize gets a file to itself.
+//
+// If rotating fails, p is still written to whichever file is open, and
+// Write returns len(p) along with the error. The file isn't rotated
+// again until it has taken another MaxSize or MaxAge, so a rotation
+// that keeps failing doesn't get tried on every write.
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.closed {
+		return 0, os.ErrClosed
+	}
+	if rf.f == nil {
+		// Rotating failed and so did opening the file again afterwards,
+		// so try again now rather than giving up for good
+		if err := rf.open(); err != nil {
+			return 0, err
+		}
+	}
+	var rotateErr error
+	if rf.due(len(p)) {
+		if rotateErr = rf.rotate(); rotateErr != nil {
+			if rf.f == nil {
+				return 0, rotateErr
+			}
+			rotateErr = fmt.Errorf("rotating %s: %w", rf.path, rotateErr)
+			rf.size, rf.opened = 0, time.Now()
+		}
+	}
+	n, err := rf.f.Write(p)
+	rf.size += int64(n)
+	if err != nil {
+		return n, err
+	}
+	return n, rotateErr
+}
+
+func (rf *RotatingFile) due(n int) bool {
+	if rf.size == 0 {
+		// Rotating an empty file would only leave an empty file behind
+		return false
+	}
+	if rf.r.MaxSize > 0 && rf.size+int64(n) > rf.r.MaxSize {
+		return true
+	}
+	return rf.r.MaxAge > 0 && time.Since(rf.opened) >= rf.r.MaxAge
+}
+
+// rotate renames the current file out of the way, opens a new one, and
+// clears out old files past MaxBackups
+func (rf *RotatingFile) rotate() error {
+	err := rf.f.Close()
+	rf.f = nil
+	if err != nil {
+		return rf.reopen(err)
+	}
+	stamp := rf.path + "." + time.Now().Format(rotatedLayout)
+	name := stamp
+	// Two rotations at the same moment would otherwise overwrite the
+	// first one. The count is padded so the names still sort in order.
+	for i := 1; exists(name); i++ {
+		name = fmt.Sprintf("%s-%03d", stamp, i)
+	}
+	if err := rename(rf.path, name); err != nil {
+		return rf.reopen(err)
+	}
+	if err := rf.open(); err != nil {
+		return rf.reopen(err)
+	}
+	return rf.prune()
+}
+
+// reopen goes back to appending to the file at path when rotating it
+// failed, so one bad rotation doesn't stop the logging for good. It
+// returns err either way, and if the file can't be opened either, Write
+// tries again next time.
+func (rf *RotatingFile) reopen(err error) error {
+	if rf.f == nil {
+		rf.open()
+	}
+	return err
+}
+
+func (rf *RotatingFile) prune() error {
+	if rf.r.MaxBackups <= 0 {
+		return nil
+	}
+	old, err := rf.rotated()
+	if err != nil {
+		return err
+	}
+	for len(old) > rf.r.MaxBackups {
+		if err := os.Remove(old[0]); err != nil {
+			return err
+		}
+		old = old[1:]
+	}
+	return nil
+}
+
+// rotated returns the files rotate has renamed path to, oldest first.
+// Anything else next to it, like app.log.lock, is left out, even though
+// its name starts the same way.
+func (rf *RotatingFile) rotated() ([]string, error) {
+	dir, base := filepath.Split(rf.path)
+	entries, err := os.ReadDir(filepath.Clean(dir))
+	if err != nil {
+		return nil, err
+	}
+	var old []string
+	for _, e := range entries {
+		if rest, ok := strings.CutPrefix(e.Name(), base+"."); ok && isStamp(rest) {
+			old = append(old, filepath.Join(dir, e.Name()))
+		}
+	}
+	// The names start with the time, so sorting them puts them oldest
+	// first
+	sort.Strings(old)
+	return old, nil
+}
+
+// isStamp reports whether s is a time in rotatedLayout, with or without
+// the -001 rotate adds when two rotations land on the same time
+func isStamp(s string) bool {
+	if len(s) < len(rotatedLayout) {
+		return false
+	}
+	if _, err := time.Parse(rotatedLayout, s[:len(rotatedLayout)]); err != nil {
+		return false
+	}
+	rest := s[len(rotatedLayout):]
+	if rest == "" {
+		return true
+	}
+	n, ok := strings.CutPrefix(rest, "-")
+	return ok && n != "" && strings.Trim(n, "0123456789") == ""
+}
+
+func exists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+// Close closes the current file. Close the Logger using it first, so
+// everything still buffered gets written.
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	rf.closed = true
+	if rf.f == nil {
+		return nil
+	}
+	err := rf.f.Close()
+	rf.f = nil
+	return err
+}
@@ -0,0 +1,196 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// backups returns the rotated files next to path, oldest first
+func backups(t *testing.T, path string) []string {
+	t.Helper()
+	old, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(old)
+	return old
+}
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestRotateBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	rf, err := OpenRotating(path, Rotation{MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each line is 6 bytes, so only one fits in a file
+	for _, line := range []string{"one..\n", "two..\n", "three\n", "four.\n", "five.\n"} {
+		if _, err := rf.Write([]byte(line)); err != nil {
+			t.Fatalf("Write(%q): %v", line, err)
+		}
+	}
+	if err := rf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readFile(t, path); got != "five.\n" {
+		t.Errorf("current file holds %q, want the last line", got)
+	}
+	// Four rotations, with the oldest two pruned
+	old := backups(t, path)
+	if len(old) != 2 {
+		t.Fatalf("kept %d rotated files, want 2: %q", len(old), old)
+	}
+	for i, want := range []string{"three\n", "four.\n"} {
+		if got := readFile(t, old[i]); got != want {
+			t.Errorf("%s holds %q, want %q", filepath.Base(old[i]), got, want)
+		}
+	}
+
+	if _, err := rf.Write([]byte("late\n")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Write after Close = %v, want %v", err, os.ErrClosed)
+	}
+}
+
+func TestRotateBigWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	rf, err := OpenRotating(path, Rotation{MaxSize: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rf.Close()
+	// A write bigger than MaxSize goes in a file of its own rather than
+	// being split up, and an empty file is never rotated
+	big := strings.Repeat("x", 10) + "\n"
+	for range 2 {
+		if _, err := rf.Write([]byte(big)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := readFile(t, path); got != big {
+		t.Errorf("current file holds %q, want %q", got, big)
+	}
+	if old := backups(t, path); len(old) != 1 {
+		t.Errorf("rotated %d times, want once: %q", len(old), old)
+	}
+}
+
+func TestRotateRecoversFromFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	rf, err := OpenRotating(path, Rotation{MaxSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rf.Close()
+	if _, err := rf.Write([]byte("first\n")); err != nil {
+		t.Fatal(err)
+	}
+	// With the file gone, renaming it out of the way fails
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	n, err := rf.Write([]byte("second\n"))
+	if err == nil {
+		t.Fatal("Write rotating a missing file succeeded")
+	}
+	// but the file is opened again and the entry goes in it, so logging
+	// carries on
+	if n != len("second\n") {
+		t.Errorf("Write after a failed rotation wrote %d bytes, want all of them", n)
+	}
+	if _, err := rf.Write([]byte("third\n")); err != nil {
+		t.Fatalf("Write after a failed rotation: %v", err)
+	}
+	if got := readFile(t, path); got != "third\n" {
+		t.Errorf("file holds %q, want %q", got, "third\n")
+	}
+	if old := backups(t, path); len(old) != 1 || readFile(t, old[0]) != "second\n" {
+		t.Errorf("rotated files %q, want one holding the entry written when rotating failed", old)
+	}
+}
+
+func TestRotateKeepsFailing(t *testing.T) {
+	failed := errors.New("rename failed")
+	rename = func(string, string) error { return failed }
+	defer func() { rename = os.Rename }()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	rf, err := OpenRotating(path, Rotation{MaxSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rf.Close()
+	var want strings.Builder
+	errs := 0
+	// Each line is 4 bytes, so two fit in a file
+	for _, line := range []string{"one\n", "two\n", "thr\n", "fou\n", "fiv\n", "six\n", "sev\n", "eig\n"} {
+		n, err := rf.Write([]byte(line))
+		if n != len(line) {
+			t.Errorf("Write(%q) wrote %d bytes, want all of them", line, n)
+		}
+		if err != nil {
+			if !errors.Is(err, failed) {
+				t.Errorf("Write(%q): %v, want %v", line, err, failed)
+			}
+			errs++
+		}
+		want.WriteString(line)
+	}
+	// Nothing is lost, and after a failure rotating waits until the file
+	// has taken another MaxSize instead of being tried on every write
+	if got := readFile(t, path); got != want.String() {
+		t.Errorf("file holds %q, want %q", got, want.String())
+	}
+	if errs != 3 {
+		t.Errorf("Write failed to rotate %d times, want 3", errs)
+	}
+	if old := backups(t, path); len(old) != 0 {
+		t.Errorf("rotated files %q, want none", old)
+	}
+}
+
+func TestRotateLeavesOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	// Files that start like a rotated file but aren't one, and an old
+	// rotated file, which is the only one pruning should touch
+	others := []string{"app.log.lock", "app.log.gz.bak", "app.log.20200101-000000.000000000.gz", "app.log.20200101-000000.000000000-x"}
+	oldest := "app.log.20200101-000000.000000000"
+	for _, name := range append(others, oldest) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rf, err := OpenRotating(path, Rotation{MaxSize: 10, MaxBackups: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rf.Close()
+	for _, line := range []string{"one..\n", "two..\n"} {
+		if _, err := rf.Write([]byte(line)); err != nil {
+			t.Fatalf("Write(%q): %v", line, err)
+		}
+	}
+
+	for _, name := range others {
+		if !exists(filepath.Join(dir, name)) {
+			t.Errorf("pruning removed %s", name)
+		}
+	}
+	if exists(filepath.Join(dir, oldest)) {
+		t.Errorf("pruning kept %s, past MaxBackups", oldest)
+	}
+}
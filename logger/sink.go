@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// A Sink is somewhere entries are written, like the console or a file
+type Sink interface {
+	Write(e Entry) error
+}
+
+// SinkFunc lets a plain function be a Sink
+type SinkFunc func(e Entry) error
+
+func (f SinkFunc) Write(e Entry) error {
+	return f(e)
+}
+
+// Format lays an entry out the way the channels lesson printed them:
+//
+//	2006-01-02T15:04:05 - [INFO] App is starting
+func Format(e Entry) string {
+	return fmt.Sprintf("%v - [%v] %v", e.Time.Format("2006-01-02T15:04:05"), e.Severity, e.Message)
+}
+
+// Text writes each entry to w as a line laid out by Format. Anything
+// with a Write([]byte) (int, error) method works, including the Writer
+// interface from the interfaces chapter.
+func Text(w io.Writer) Sink {
+	return SinkFunc(func(e Entry) error {
+		_, err := fmt.Fprintln(w, Format(e))
+		return err
+	})
+}
+
+// Console writes entries to stdout like Text does. It looks up
+// os.Stdout for every entry instead of once, so it follows stdout if
+// it's swapped out, the way the lesson runner captures output.
+func Console() Sink {
+	return SinkFunc(func(e Entry) error {
+		_, err := fmt.Fprintln(os.Stdout, Format(e))
+		return err
+	})
+}
+
+// JSONLines writes each entry to w as a JSON object on its own line,
+// like {"time":"...","severity":"INFO","message":"App is starting"}
+func JSONLines(w io.Writer) Sink {
+	enc := json.NewEncoder(w)
+	return SinkFunc(func(e Entry) error {
+		return enc.Encode(e)
+	})
+}
+
+// AtLeast wraps s so it only gets entries of severity min or worse
+func AtLeast(min Severity, s Sink) Sink {
+	return SinkFunc(func(e Entry) error {
+		if e.Severity < min {
+			return nil
+		}
+		return s.Write(e)
+	})
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSinks(t *testing.T) {
+	var text, json strings.Builder
+	l := New(Text(&text), AtLeast(Warn, JSONLines(&json)))
+	l.Info("App is starting")
+	l.Error("Out of %s", "coffee")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The times are whenever the test ran, so only the ends of the
+	// lines are checked. TestFormat covers the rest.
+	lines := strings.Split(strings.TrimSpace(text.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Text wrote %q, want 2 lines", text.String())
+	}
+	for i, want := range []string{" - [INFO] App is starting", " - [ERROR] Out of coffee"} {
+		if !strings.HasSuffix(lines[i], want) {
+			t.Errorf("Text line %d is %q, want it to end %q", i, lines[i], want)
+		}
+	}
+
+	got := strings.TrimSpace(json.String())
+	if strings.Count(got, "\n") != 0 || !strings.HasSuffix(got, `"severity":"ERROR","message":"Out of coffee"}`) {
+		t.Errorf("JSONLines at Warn wrote %q, want only the error", got)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	e := Entry{Time: time.Date(2024, 11, 30, 9, 5, 0, 0, time.UTC), Severity: Warn, Message: "Low on coffee"}
+	if got, want := Format(e), "2024-11-30T09:05:00 - [WARNING] Low on coffee"; got != want {
+		t.Errorf("Format = %q, want %q", got, want)
+	}
+}
@@ -1,5 +1,114 @@
 package mapsStructs
 
-func Maps() {
+import (
+	"fmt"
+	"sort"
 
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+var mapLessons = []lessons.Lesson{
+	{ID: "mapsStructs/map-literals", Title: "Making maps", Run: mapLiterals, Teaches: []string{"maps"}},
+	{ID: "mapsStructs/comma-ok", Title: "Looking up a key with comma ok", Run: mapCommaOk, Teaches: []string{"comma ok"}, Requires: []string{"maps", "if"}, Quiz: &lessons.Quiz{
+		Distractors: []string{"Australia\n<nil>\nfalse\nAustralia true", "Australia\n\"\"\ntrue\nAustralia true"},
+	}},
+	{ID: "mapsStructs/delete", Title: "Deleting from a map", Run: mapDelete, Requires: []string{"comma ok", "range"}},
+	{ID: "mapsStructs/map-order", Title: "Maps have no order", Run: mapOrder, Requires: []string{"maps", "range", "for loops"}, Unordered: true},
+}
+
+func mapLiterals() {
+	// A map literal lists its key value pairs. The trailing comma
+	// after the last pair is required when the closing brace is on
+	// its own line
+	statePopulations := map[string]int{
+		"California": 39250017,
+		"Texas":      27862596,
+		"Florida":    20612439,
+	}
+	fmt.Println(statePopulations["Texas"])
+
+	// Keys can be any type that can be compared with ==, so arrays
+	// work but slices, maps and functions don't
+	m := map[[2]int]string{
+		{0, 0}: "origin",
+	}
+	fmt.Println(m[[2]int{0, 0}])
+
+	// make gives us an empty map to fill in. A map declared with var
+	// and never made is nil, and writing to a nil map panics
+	birds := make(map[string]string)
+	birds["name"] = "Emu"
+	fmt.Println(birds, len(birds))
+}
+
+func mapCommaOk() {
+	bird := map[string]string{
+		"name":   "Emu",
+		"origin": "Australia",
+	}
+	fmt.Println(bird["origin"])
+
+	// Asking for a key that isn't there isn't an error. We just get
+	// the zero value for the value type, which for strings is "". %q
+	// puts it in quotes so we can see it
+	fmt.Printf("%q\n", bird["speed"])
+
+	// To tell a missing key from one set to the zero value, we ask
+	// for a second value, conventionally called ok
+	_, ok := bird["speed"]
+	fmt.Println(ok)
+
+	// Usually the lookup goes right in an if, so the variables only
+	// live as long as they're needed
+	if pop, ok := bird["origin"]; ok {
+		fmt.Println(pop, ok)
+	}
+}
+
+func mapDelete() {
+	bird := map[string]string{
+		"name":   "Emu",
+		"origin": "Australia",
+	}
+
+	// delete is a built in function, and doesn't complain if the key
+	// isn't there
+	delete(bird, "origin")
+	delete(bird, "speed")
+	_, ok := bird["origin"]
+	fmt.Println(len(bird), ok)
+
+	// Deleting while ranging over a map is safe. Entries that haven't
+	// been reached yet won't come up once they're deleted
+	for k := range bird {
+		delete(bird, k)
+	}
+	fmt.Println(len(bird))
+}
+
+func mapOrder() {
+	statePopulations := map[string]int{
+		"California": 39250017,
+		"Texas":      27862596,
+		"Florida":    20612439,
+		"New York":   19745289,
+	}
+
+	// The order a map ranges in isn't defined, and the runtime goes
+	// out of its way to mix it up from run to run, so code can't
+	// come to depend on it
+	for state, pop := range statePopulations {
+		fmt.Println(state, pop)
+	}
+
+	// When the order matters, we sort the keys ourselves and use them
+	// to walk the map
+	states := make([]string, 0, len(statePopulations))
+	for state := range statePopulations {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	for _, state := range states {
+		fmt.Println("sorted:", state)
+	}
 }
@@ -0,0 +1,14 @@
+package mapsStructs
+
+import (
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+func init() {
+	ls := append(append([]lessons.Lesson{}, mapLessons...), structLessons...)
+	lessons.Register(lessons.Chapter{Name: "mapsStructs", Title: "MAPS AND STRUCTS", Order: 25}, ls...)
+}
+
+func MapsStructsLessons() {
+	lessons.RunChapter("mapsStructs")
+}
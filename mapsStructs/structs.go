@@ -1,5 +1,166 @@
 package mapsStructs
 
-func Structs() {
+import (
+	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/nicolasjhampton/hellogo/lessons"
+	"github.com/nicolasjhampton/hellogo/validate"
+)
+
+var structLessons = []lessons.Lesson{
+	{ID: "mapsStructs/struct-literals", Title: "Making structs", Run: structLiterals, Teaches: []string{"structs"}},
+	{ID: "mapsStructs/struct-copies", Title: "Structs are values", Run: structCopies, Requires: []string{"structs"}, Quiz: &lessons.Quiz{
+		Distractors: []string{"{Tom Baker}\n{Tom Baker}", "{John Perwee}\n{John Perwee}"},
+	}},
+	{ID: "mapsStructs/embedding", Title: "Embedding instead of inheritance", Run: structEmbedding, Teaches: []string{"embedding"}, Requires: []string{"structs"}},
+	{ID: "mapsStructs/anonymous-structs", Title: "Anonymous structs", Run: structAnonymous, Requires: []string{"structs", "range"}},
+	{ID: "mapsStructs/tags", Title: "Struct tags", Run: structTags, Teaches: []string{"struct tags"}, Requires: []string{"embedding"}},
+	{ID: "mapsStructs/validating", Title: "Validating structs with tags", Run: structValidating, Requires: []string{"struct tags", "range"}},
+}
+
+// A struct gathers any mix of types under one name. Fields starting
+// with a lowercase letter are only visible inside this package, the
+// same as everything else in Go
+type Doctor struct {
+	number     int
+	actorName  string
+	episodes   []string
+	companions []string
+}
+
+func structLiterals() {
+	// Naming the fields makes the literal keep working if fields get
+	// added or moved around later, and any we leave out start at
+	// their zero values, like episodes here
+	aDoctor := Doctor{
+		number:    3,
+		actorName: "Jon Pertwee",
+		companions: []string{
+			"Liz Shaw",
+			"Jo Grant",
+			"Sarah Jane Smith",
+		},
+	}
+	fmt.Println(aDoctor)
+	fmt.Println(aDoctor.actorName, len(aDoctor.episodes))
+
+	// Positional literals work too, but they break as soon as the
+	// struct changes, so they're best avoided outside tiny structs
+	anotherDoctor := Doctor{4, "Tom Baker", nil, nil}
+	fmt.Println(anotherDoctor.number, anotherDoctor.actorName)
+}
+
+func structCopies() {
+	// Unlike maps and slices, structs are values. Assigning one makes
+	// a whole new copy, so changing the copy leaves the original alone
+	aDoctor := struct{ name string }{name: "John Perwee"}
+	anotherDoctor := aDoctor
+	anotherDoctor.name = "Tom Baker"
+	fmt.Println(aDoctor)
+	fmt.Println(anotherDoctor)
+
+	// To share one struct, we need a pointer to it. That's coming up
+	// in the pointers chapter
+}
+
+// Go doesn't have inheritance. Instead a struct can embed another
+// type, and the embedded type's fields and methods get promoted, so
+// they can be used as if Bird declared them itself
+type Animal struct {
+	Name   string `required:"true" max:"100"`
+	Origin string
+}
+
+type Bird struct {
+	Animal
+	SpeedKPH float32 `min:"0" max:"120"`
+	CanFly   bool
+}
+
+func structEmbedding() {
+	// Promoted fields can be set directly...
+	b := Bird{}
+	b.Name = "Emu"
+	b.Origin = "Australia"
+	b.SpeedKPH = 48
+	b.CanFly = false
+	fmt.Println(b)
+
+	// ...but a literal has to spell out the embedded struct, using the
+	// type name as the field name
+	b = Bird{
+		Animal: Animal{
+			Name:   "Emu",
+			Origin: "Australia",
+		},
+		SpeedKPH: 48,
+		CanFly:   false,
+	}
+	fmt.Println(b.Name, b.Animal.Name)
+
+	// This is composition, not inheritance. A Bird "has an" Animal, it
+	// isn't one, so it can't be used where an Animal is expected
+	// var a Animal = b // won't compile
+	var a Animal = b.Animal
+	fmt.Println(a)
+}
+
+func structAnonymous() {
+	// A struct can be declared and used in one go, without naming the
+	// type. Handy for one-off groupings, like shaping some JSON or a
+	// table of test cases
+	aDoctor := struct{ name string }{name: "John Perwee"}
+	fmt.Println(aDoctor)
+
+	cases := []struct {
+		in   int
+		want string
+	}{
+		{1, "one"},
+		{2, "two"},
+	}
+	for _, c := range cases {
+		fmt.Println(c.in, c.want)
+	}
+}
+
+func structTags() {
+	// Tags are strings attached to fields. They don't do anything on
+	// their own. Other code reads them at runtime with reflection,
+	// which is how packages like encoding/json know what to do
+	t := reflect.TypeOf(Animal{})
+	field, ok := t.FieldByName("Name")
+	fmt.Println(field.Name, field.Tag, ok)
+
+	// By convention a tag is a list of key:"value" pairs separated by
+	// spaces, which is the format Get understands. A bare word like
+	// `required max:"100"` can't be read by Get, and go vet will
+	// complain about it
+	fmt.Println(field.Tag.Get("max"))
+
+	// Get can't tell a missing key from an empty value, so Lookup
+	// hands back an ok too, like a map does
+	required, ok := field.Tag.Lookup("required")
+	fmt.Println(required, ok)
+	_, ok = field.Tag.Lookup("min")
+	fmt.Println(ok)
+}
+
+func structValidating() {
+	// The validate package puts tags like the ones on Animal to work.
+	// It walks the struct with reflection, the same way structTags
+	// does, and checks each field against the rules in its tag
+	emu := Bird{Animal: Animal{Name: "Emu", Origin: "Australia"}, SpeedKPH: 48}
+	fmt.Println(validate.Validate(emu))
+
+	// It goes into embedded structs, and reports every broken rule
+	// with the path to the field, instead of stopping at the first
+	flock := []Bird{emu, {SpeedKPH: 500}, {Animal: Animal{Name: strings.Repeat("Emu", 40)}}}
+	for i, b := range flock {
+		if err := validate.Validate(b); err != nil {
+			fmt.Println(i, err)
+		}
+	}
 }
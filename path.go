@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+// pathCommand works out which lessons to do, and in what order, to be
+// ready for the lessons given. With no lessons it orders the whole book.
+func pathCommand(args []string) error {
+	fs := newFlagSet("path", "[lessons...]")
+	f := filterFlags(fs)
+	o := learnerFlags(fs)
+	all := fs.Bool("all", false, "include lessons you've already finished")
+	step := fs.Bool("step", false, "walk through the path right away, like the step command")
+	targets, err := selectLessons(fs, f, args)
+	if err != nil {
+		return err
+	}
+	_, learner, err := o.load()
+	if err != nil {
+		return err
+	}
+	done := learner.Completed
+	if *all {
+		done = func(string) bool { return false }
+	}
+	path, err := lessons.Path(targets, done)
+	if err != nil {
+		return err
+	}
+	if len(path) == 0 {
+		fmt.Println("You've already finished all of that!")
+		return nil
+	}
+	if *step {
+		return runStepper(path, lessons.DefaultOptions, o)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for i, l := range path {
+		fmt.Fprintf(w, "%3d. [%s] %s\t%s\t%s\n", i+1, mark(learner, l.ID), l.ID, l.Title, strings.Join(l.Teaches, ", "))
+	}
+	return w.Flush()
+}
+
+// warnAhead lets the learner know when ls jumps ahead of them: when a
+// lesson needs others that they haven't finished and that don't come
+// before it in ls. It only warns about the first one, since the rest
+// usually need the same things.
+func warnAhead(w io.Writer, ls []lessons.Lesson, o *learnerOptions) {
+	_, learner, err := o.load()
+	if err != nil {
+		// Not knowing what they've done isn't worth stopping a run over
+		return
+	}
+	earlier := map[string]bool{}
+	done := func(id string) bool {
+		return earlier[id] || learner.Completed(id)
+	}
+	for _, l := range ls {
+		missing, err := lessons.Missing(l, done)
+		if err != nil {
+			fmt.Fprintf(w, "warning: %v\n\n", err)
+			return
+		}
+		earlier[l.ID] = true
+		if len(missing) == 0 {
+			continue
+		}
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "Heads up: %s builds on lessons you haven't done yet:\n", l.ID)
+		for _, m := range missing {
+			fmt.Fprintf(tw, "  %s\t%s\n", m.ID, m.Title)
+		}
+		fmt.Fprintf(tw, "Run \"hellogo path %s\" to see the way there.\n\n", l.ID)
+		tw.Flush()
+		return
+	}
+}
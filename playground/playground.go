@@ -0,0 +1,215 @@
+// Package playground serves the lessons as a local web page, for
+// clicking through them instead of scrolling through a terminal. Each
+// lesson's page shows its source with a Run button. The lesson runs on
+// the server, and what it prints is streamed back to the browser a line
+// at a time with Server-Sent Events, followed by anything the runner
+// has to say about it, like a panic or a timeout.
+package playground
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+// A Server is an http.Handler for the playground
+type Server struct {
+	opts lessons.Options
+	mux  *http.ServeMux
+	// OnResult, if it's set, is called with the result of every lesson
+	// run from the browser, so runs can count toward progress
+	OnResult func(lessons.Result)
+}
+
+// New returns a Server that runs lessons with opts
+func New(opts lessons.Options) *Server {
+	s := &Server{opts: opts, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /{$}", s.index)
+	s.mux.HandleFunc("GET /lessons/{id...}", s.lesson)
+	s.mux.HandleFunc("GET /run/{id...}", s.run)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) index(w http.ResponseWriter, r *http.Request) {
+	s.render(w, pageData{Chapters: lessons.Chapters()})
+}
+
+func (s *Server) lesson(w http.ResponseWriter, r *http.Request) {
+	l, ok := lessons.Lookup(r.PathValue("id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	data := pageData{Chapters: lessons.Chapters(), Lesson: &l}
+	code, err := lessons.Source(l)
+	if err != nil {
+		data.SourceErr = err.Error()
+	} else {
+		data.Code = &code
+	}
+	all := lessons.All()
+	for i, other := range all {
+		if other.ID != l.ID {
+			continue
+		}
+		if i > 0 {
+			data.Prev = &all[i-1]
+		}
+		if i+1 < len(all) {
+			data.Next = &all[i+1]
+		}
+	}
+	s.render(w, data)
+}
+
+type pageData struct {
+	Chapters   []lessons.Chapter
+	Lesson     *lessons.Lesson
+	Code       *lessons.Code
+	SourceErr  string
+	Prev, Next *lessons.Lesson
+}
+
+func (s *Server) render(w http.ResponseWriter, data pageData) {
+	// Rendering into a buffer first means a template error turns into
+	// a 500 instead of half a page
+	var b bytes.Buffer
+	if err := pageTemplate.Execute(&b, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	b.WriteTo(w)
+}
+
+// run runs a lesson and streams what happens as events:
+//
+//	line     a line the lesson printed
+//	panic    the lesson panicked, as JSON with the message and stack
+//	timeout  the lesson didn't finish in time, with the runner's report
+//	leak     the lesson left goroutines running, with their stacks
+//	done     the lesson is over, as JSON with its status and duration
+//
+// The browser closes the stream when it sees done. Otherwise
+// EventSource would reconnect and run the lesson again.
+func (s *Server) run(w http.ResponseWriter, r *http.Request) {
+	l, ok := lessons.Lookup(r.PathValue("id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	es := &eventStream{w: w, rc: http.NewResponseController(w)}
+
+	// Capture only lets one lesson have stdout at a time, so a second
+	// browser tab waits here for the first lesson to finish
+	out := &lineWriter{send: func(line string) { es.send("line", line) }}
+	var res lessons.Result
+	err := lessons.Capture(out, func() {
+		res = lessons.RunLesson(l, s.opts)
+	})
+	out.flush()
+	if err != nil {
+		log.Printf("playground: capturing %s: %v", l.ID, err)
+	}
+
+	switch {
+	case res.Panic != nil && res.Status != lessons.TimedOut:
+		es.sendJSON("panic", struct {
+			Message  string `json:"message"`
+			Stack    string `json:"stack"`
+			Expected bool   `json:"expected"`
+		}{fmt.Sprint(res.Panic), res.Stack, res.Status == lessons.Passed})
+	case res.Status == lessons.TimedOut:
+		es.send("timeout", report(res))
+	case res.Status == lessons.Failed:
+		// Failed without panicking, like a strict run that leaked
+		es.send("line", fmt.Sprintf("FAIL %s: %v", l.ID, res.Err))
+	}
+	if len(res.Leaks) > 0 {
+		var stacks []string
+		for _, g := range res.Leaks {
+			stacks = append(stacks, g.Stack)
+		}
+		es.send("leak", strings.Join(stacks, "\n\n"))
+	}
+	es.sendJSON("done", struct {
+		Status   string `json:"status"`
+		Duration string `json:"duration"`
+	}{res.Status.String(), res.Duration.Round(time.Microsecond).String()})
+
+	if s.OnResult != nil {
+		s.OnResult(res)
+	}
+}
+
+// report is what the terminal runner would print about res
+func report(res lessons.Result) string {
+	var b strings.Builder
+	lessons.Report(&b, res)
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// An eventStream writes Server-Sent Events, flushing each one so the
+// browser gets it right away
+type eventStream struct {
+	w  io.Writer
+	rc *http.ResponseController
+}
+
+func (es *eventStream) send(event, data string) {
+	fmt.Fprintf(es.w, "event: %s\n", event)
+	// A data field can't hold a line break, so each line of the data
+	// gets its own, and the browser joins them back up with newlines
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r", ""), "\n") {
+		fmt.Fprintf(es.w, "data: %s\n", line)
+	}
+	fmt.Fprint(es.w, "\n")
+	es.rc.Flush()
+}
+
+func (es *eventStream) sendJSON(event string, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err) // only ever given structs of strings
+	}
+	es.send(event, string(b))
+}
+
+// A lineWriter hands each complete line written to it to send
+type lineWriter struct {
+	send func(line string)
+	buf  []byte
+}
+
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	lw.buf = append(lw.buf, p...)
+	for {
+		i := bytes.IndexByte(lw.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		lw.send(string(lw.buf[:i]))
+		lw.buf = lw.buf[i+1:]
+	}
+}
+
+// flush sends whatever was printed after the last line break
+func (lw *lineWriter) flush() {
+	if len(lw.buf) > 0 {
+		lw.send(string(lw.buf))
+		lw.buf = nil
+	}
+}
@@ -0,0 +1,123 @@
+package playground
+
+import (
+	"html/template"
+
+	"github.com/nicolasjhampton/hellogo/book"
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+// The styles, title and highlight come from the book, so the playground
+// looks the same as the exported site
+var funcs = template.FuncMap{
+	"style":     func() template.CSS { return template.CSS(book.Style) },
+	"title":     book.Title,
+	"highlight": book.Highlight,
+	"source":    lessons.RepoPath,
+}
+
+var pageTemplate = template.Must(template.New("page").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{with .Lesson}}{{.Title}} - {{end}}hellogo playground</title>
+<style>
+{{style}}
+nav .current { font-weight: bold; }
+main { flex-grow: 1; }
+#output { background: #222; color: #eee; min-height: 1.4em; white-space: pre-wrap; }
+#output .panic { color: #ff6b6b; }
+#output .expected { color: #e0a800; }
+#output .timeout { color: #ff9f43; }
+#output .leak { color: #aaa; }
+#output .done { color: #7bed9f; }
+button { font-size: 16px; padding: 0.3rem 1.2rem; cursor: pointer; }
+</style>
+</head>
+<body>
+<nav>
+<a href="/"><strong>hellogo playground</strong></a>
+<ul>
+{{- $current := ""}}{{with .Lesson}}{{$current = .ID}}{{end}}
+{{- range .Chapters}}
+<li>{{title .}}
+<ul>
+{{- range .Lessons}}
+<li><a href="/lessons/{{.ID}}"{{if eq .ID $current}} class="current"{{end}}>{{.Title}}</a></li>
+{{- end}}
+</ul>
+</li>
+{{- end}}
+</ul>
+</nav>
+<main>
+{{- with .Lesson}}
+<h1>{{.Title}}</h1>
+<p class="where"><code>{{.ID}}</code>{{with $.Code}}, from {{source .File}} line {{.Line}}{{end}}</p>
+{{- with $.Code}}
+<pre><code>{{highlight .Text}}</code></pre>
+{{- else}}
+<p><em>The source isn't available: {{$.SourceErr}}</em></p>
+{{- end}}
+<p><button id="run" data-id="{{.ID}}">Run</button></p>
+<pre id="output"></pre>
+<div class="pager">
+<span>{{with $.Prev}}<a href="/lessons/{{.ID}}">&larr; {{.Title}}</a>{{end}}</span>
+<span>{{with $.Next}}<a href="/lessons/{{.ID}}">{{.Title}} &rarr;</a>{{end}}</span>
+</div>
+<script>
+const button = document.getElementById("run");
+const output = document.getElementById("output");
+
+function show(text, className) {
+	const span = document.createElement("span");
+	span.textContent = text + "\n";
+	if (className) span.className = className;
+	output.appendChild(span);
+}
+
+button.addEventListener("click", () => {
+	output.textContent = "";
+	button.disabled = true;
+	const events = new EventSource("/run/" + button.dataset.id);
+	events.addEventListener("line", e => show(e.data));
+	events.addEventListener("panic", e => {
+		const p = JSON.parse(e.data);
+		if (p.expected) {
+			show("(recovered the expected panic: " + p.message + ")", "expected");
+		} else {
+			show("panic: " + p.message + "\n\n" + p.stack, "panic");
+		}
+	});
+	events.addEventListener("timeout", e => show(e.data, "timeout"));
+	events.addEventListener("leak", e => show("Left goroutines running:\n\n" + e.data, "leak"));
+	events.addEventListener("done", e => {
+		const d = JSON.parse(e.data);
+		show("[" + d.status + " in " + d.duration + "]", d.status == "pass" ? "done" : "panic");
+		events.close();
+		button.disabled = false;
+	});
+	events.onerror = () => {
+		show("[lost the connection to hellogo serve]", "panic");
+		events.close();
+		button.disabled = false;
+	};
+});
+</script>
+{{- else}}
+<h1>hellogo playground</h1>
+<p>Pick a lesson to see its code, then run it to watch what it prints.
+Lessons run on this machine, one at a time.</p>
+{{- range .Chapters}}
+<h2>{{title .}}</h2>
+<ul>
+{{- range .Lessons}}
+<li><a href="/lessons/{{.ID}}">{{.Title}}</a> <code>{{.ID}}</code></li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+</main>
+</body>
+</html>
+`))
@@ -3,27 +3,36 @@ package pointers
 import (
 	"fmt"
 	"unsafe"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
 )
 
-var pointerLessons = []func(){
-	pointerBasics,
-	pointerCreation,
-	pointerDereferencing,
-	pointerArithimetic,
-	pointerUnsafe,
-	pointerStructs,
-	pointerNew,
-	pointerAccessingFields,
-	pointerSlices,
-	pointerMaps,
+var pointerLessons = []lessons.Lesson{
+	{ID: "pointers/basics", Title: "Assignment copies values", Run: pointerBasics},
+	{ID: "pointers/creation", Title: "Creating a pointer", Run: pointerCreation, Teaches: []string{"pointers"}, Requires: []string{"pointers/basics"}},
+	{ID: "pointers/dereferencing", Title: "Dereferencing a pointer", Run: pointerDereferencing, Teaches: []string{"dereferencing"}, Requires: []string{"pointers"}},
+	{ID: "pointers/arithmetic", Title: "No pointer arithmetic", Run: pointerArithimetic, Requires: []string{"dereferencing"}},
+	{ID: "pointers/unsafe", Title: "Pointer arithmetic with unsafe", Run: pointerUnsafe, Requires: []string{"pointers/arithmetic"}},
+	{ID: "pointers/structs", Title: "Pointers to structs", Run: pointerStructs, Teaches: []string{"pointers to structs"}, Requires: []string{"pointers", "structs"}},
+	{ID: "pointers/new", Title: "Nil pointers and new", Run: pointerNew, Requires: []string{"pointers to structs"}},
+	{ID: "pointers/accessing-fields", Title: "Accessing fields through a pointer", Run: pointerAccessingFields, Requires: []string{"pointers/new", "dereferencing"}},
+	{ID: "pointers/slices", Title: "Arrays copy, slices share", Run: pointerSlices, Requires: []string{"pointers/basics"}, Quiz: &lessons.Quiz{
+		Distractors: []string{
+			"[1 2 3] [1 2 3]\n[1 42 3] [1 42 3]\n[1 2 3] [1 2 3]\n[1 42 3] [1 2 3]",
+			"[1 2 3] [1 2 3]\n[1 42 3] [1 2 3]\n[1 2 3] [1 2 3]\n[1 42 3] [1 2 3]",
+		},
+	}},
+	{ID: "pointers/maps", Title: "Maps share their data", Run: pointerMaps, Requires: []string{"pointers/slices", "maps"}, Quiz: &lessons.Quiz{
+		Distractors: []string{"map[baz:buz foo:bar] map[baz:buz foo:bar]\nmap[baz:buz foo:qux] map[baz:buz foo:bar]"},
+	}},
+}
+
+func init() {
+	lessons.Register(lessons.Chapter{Name: "pointers", Title: "POINTERS", Order: 60}, pointerLessons...)
 }
 
 func PointerLessons() {
-	fmt.Println("////////////////////////////*POINTERS*////////////////////////////")
-	for _, lesson := range pointerLessons {
-		lesson()
-		fmt.Println("------------------------------------------------------------------")
-	}
+	lessons.RunChapter("pointers")
 }
 
 func pointerBasics() {
@@ -0,0 +1,169 @@
+// Package progress remembers how far each learner has gotten through
+// the book. Everything lives in one JSON file on the learner's machine,
+// keyed by user name, so a shared machine keeps everyone's place
+// separately.
+package progress
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+// A Record is what's known about one learner and one lesson
+type Record struct {
+	// Completed is when the lesson first passed. It's nil until then.
+	Completed *time.Time `json:"completed,omitempty"`
+	// LastRun and LastStatus are from the most recent run
+	LastRun    time.Time `json:"last_run"`
+	LastStatus string    `json:"last_status"`
+	Runs       int       `json:"runs"`
+}
+
+// An Answer is one quiz question a learner answered
+type Answer struct {
+	Lesson  string    `json:"lesson"`
+	Correct bool      `json:"correct"`
+	At      time.Time `json:"at"`
+}
+
+// A Learner is everything recorded for one user
+type Learner struct {
+	Lessons map[string]*Record `json:"lessons"`
+	// Quiz is every quiz answer, oldest first
+	Quiz []Answer `json:"quiz,omitempty"`
+}
+
+// A State is the whole progress file
+type State struct {
+	path     string
+	Learners map[string]*Learner `json:"learners"`
+}
+
+// DefaultPath is where the progress file goes unless told otherwise,
+// in the user's config directory
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "hellogo", "progress.json")
+}
+
+// CurrentUser is the name progress is recorded under by default
+func CurrentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "learner"
+}
+
+// Load reads the progress file at path. A file that doesn't exist yet is
+// just an empty State.
+func Load(path string) (*State, error) {
+	s := &State{path: path, Learners: map[string]*Learner{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	if s.Learners == nil {
+		s.Learners = map[string]*Learner{}
+	}
+	return s, nil
+}
+
+// Save writes the state back to the file it was loaded from. It writes
+// to a temporary file first and renames it into place, so a crash
+// halfway through can't leave a corrupt progress file behind.
+func (s *State) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Learner returns the learner with the given name, adding them if
+// they're new
+func (s *State) Learner(name string) *Learner {
+	l, ok := s.Learners[name]
+	if !ok {
+		l = &Learner{}
+		s.Learners[name] = l
+	}
+	if l.Lessons == nil {
+		l.Lessons = map[string]*Record{}
+	}
+	return l
+}
+
+// Record notes the outcome of a lesson run. A lesson counts as
+// completed the first time it passes, and stays completed even if a
+// later run fails.
+func (l *Learner) Record(res lessons.Result, at time.Time) {
+	r, ok := l.Lessons[res.Lesson.ID]
+	if !ok {
+		r = &Record{}
+		l.Lessons[res.Lesson.ID] = r
+	}
+	r.Runs++
+	r.LastRun = at
+	r.LastStatus = res.Status.String()
+	if res.Status == lessons.Passed && r.Completed == nil {
+		r.Completed = &at
+	}
+}
+
+// Completed reports whether the learner has finished a lesson
+func (l *Learner) Completed(id string) bool {
+	r, ok := l.Lessons[id]
+	return ok && r.Completed != nil
+}
+
+// RecordAnswer adds a quiz answer to the learner's history
+func (l *Learner) RecordAnswer(id string, correct bool, at time.Time) {
+	l.Quiz = append(l.Quiz, Answer{Lesson: id, Correct: correct, At: at})
+}
+
+// Score returns how many quiz answers the learner got right out of how
+// many they've given
+func (l *Learner) Score() (correct, total int) {
+	for _, a := range l.Quiz {
+		if a.Correct {
+			correct++
+		}
+	}
+	return correct, len(l.Quiz)
+}
+
+// NextLesson returns the first lesson in ls the learner hasn't
+// completed, or false if they've done them all
+func (l *Learner) NextLesson(ls []lessons.Lesson) (lessons.Lesson, bool) {
+	for _, lesson := range ls {
+		if !l.Completed(lesson.ID) {
+			return lesson, true
+		}
+	}
+	return lessons.Lesson{}, false
+}
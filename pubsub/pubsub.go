@@ -0,0 +1,294 @@
+// Package pubsub is a broker for passing messages between goroutines by
+// topic, built from the channels and select the channels chapter
+// teaches. Publishers send to a named topic, and every subscriber to
+// that topic gets its own copy on its own buffered channel:
+//
+//	b := pubsub.New[string](pubsub.DefaultOptions)
+//	sub := b.Subscribe("news")
+//	go func() {
+//		for msg := range sub.C {
+//			fmt.Println(msg)
+//		}
+//	}()
+//	b.Publish("news", "hello")
+//	b.Close()
+//
+// Closing the broker closes every subscriber's channel, so a range over
+// one ends the way it does in the channels/range lesson.
+//
+// A subscriber that falls behind fills up its buffer. What happens to
+// messages for it after that is up to the SlowSubscriber policy, so one
+// stuck consumer doesn't have to hold up the rest.
+package pubsub
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrClosed is returned when publishing to a closed broker
+var ErrClosed = errors.New("pubsub: broker is closed")
+
+// A Policy is what publishing does for a subscriber whose buffer is full
+type Policy int
+
+const (
+	// Block waits for the subscriber to make room. Every other
+	// subscriber waits along with it.
+	Block Policy = iota
+	// BlockTimeout waits for room for up to Options.Timeout, then drops
+	// the message for that subscriber
+	BlockTimeout
+	// DropNewest drops the message being published for that subscriber
+	DropNewest
+	// DropOldest drops the message that's been waiting longest for that
+	// subscriber, to make room for the new one
+	DropOldest
+	// Disconnect unsubscribes the subscriber, closing its channel. It
+	// can subscribe again once it's caught up.
+	Disconnect
+)
+
+func (p Policy) String() string {
+	switch p {
+	case Block:
+		return "block"
+	case BlockTimeout:
+		return "block-timeout"
+	case DropNewest:
+		return "drop-newest"
+	case DropOldest:
+		return "drop-oldest"
+	case Disconnect:
+		return "disconnect"
+	}
+	return fmt.Sprintf("Policy(%d)", int(p))
+}
+
+// Options control each subscriber's buffer and what happens when it
+// fills up. Anything left at zero is taken from DefaultOptions, except
+// SlowSubscriber, since Block is a policy too.
+type Options struct {
+	// BufferSize is how many messages can be waiting for a subscriber
+	BufferSize     int
+	SlowSubscriber Policy
+	// Timeout is how long BlockTimeout waits
+	Timeout time.Duration
+}
+
+// DefaultOptions give every subscriber room for 16 messages, and drop
+// the oldest for a subscriber that lets them pile up
+var DefaultOptions = Options{
+	BufferSize:     16,
+	SlowSubscriber: DropOldest,
+	Timeout:        100 * time.Millisecond,
+}
+
+// A Broker passes messages of type T from publishers to subscribers.
+// It's safe to use from any number of goroutines.
+type Broker[T any] struct {
+	opts Options
+
+	// mu is held for reading while publishing and for writing while
+	// subscribing, unsubscribing or closing, so nothing is ever sent on
+	// a closed channel
+	mu     sync.RWMutex
+	topics map[string]map[*Subscription[T]]bool
+	closed bool
+
+	// quit is closed as soon as Close is called, so publishers blocked
+	// on a full subscriber let go of mu and Close can take it
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+// New returns a Broker with opts
+func New[T any](opts Options) *Broker[T] {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = DefaultOptions.BufferSize
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultOptions.Timeout
+	}
+	return &Broker[T]{opts: opts, topics: map[string]map[*Subscription[T]]bool{}, quit: make(chan struct{})}
+}
+
+// A Subscription is one subscriber to a topic
+type Subscription[T any] struct {
+	// C is where the subscriber's messages arrive. It's closed once the
+	// subscriber unsubscribes or the broker is closed.
+	C <-chan T
+
+	Topic string
+
+	b  *Broker[T]
+	ch chan T
+	// done is closed as soon as the subscriber is on its way out, so a
+	// publisher blocked on ch gives up instead of holding up the
+	// unsubscribe
+	done    chan struct{}
+	leaving sync.Once
+	dropped atomic.Uint64
+}
+
+// Subscribe starts a subscription to topic. Subscribing to a closed
+// broker gives a subscription whose channel is already closed.
+func (b *Broker[T]) Subscribe(topic string) *Subscription[T] {
+	ch := make(chan T, b.opts.BufferSize)
+	s := &Subscription[T]{C: ch, Topic: topic, b: b, ch: ch, done: make(chan struct{})}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		s.leave()
+		close(ch)
+		return s
+	}
+	if b.topics[topic] == nil {
+		b.topics[topic] = map[*Subscription[T]]bool{}
+	}
+	b.topics[topic][s] = true
+	return s
+}
+
+// Unsubscribe stops the subscription and closes its channel. Messages
+// already in the buffer can still be received. It's fine to call more
+// than once, or after the broker is closed.
+func (s *Subscription[T]) Unsubscribe() {
+	s.leave()
+	b := s.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subs := b.topics[s.Topic]
+	if !subs[s] {
+		// Already gone, and its channel closed with it
+		return
+	}
+	delete(subs, s)
+	if len(subs) == 0 {
+		delete(b.topics, s.Topic)
+	}
+	close(s.ch)
+}
+
+func (s *Subscription[T]) leave() {
+	s.leaving.Do(func() { close(s.done) })
+}
+
+// Dropped is how many messages the subscriber missed because its
+// buffer was full
+func (s *Subscription[T]) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Publish sends msg to every subscriber to topic, and returns how many
+// of them got it. Nobody being subscribed isn't an error, the message
+// just goes nowhere.
+func (b *Broker[T]) Publish(topic string, msg T) (int, error) {
+	var slow []*Subscription[T]
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return 0, ErrClosed
+	}
+	sent := 0
+	for s := range b.topics[topic] {
+		ok, disconnect := b.deliver(s, msg)
+		if ok {
+			sent++
+		}
+		if disconnect {
+			slow = append(slow, s)
+		}
+	}
+	b.mu.RUnlock()
+
+	// Unsubscribing needs the write lock, so it has to wait until
+	// publishing has let go of the read lock
+	for _, s := range slow {
+		s.Unsubscribe()
+	}
+	return sent, nil
+}
+
+// deliver sends msg to s according to the slow subscriber policy. The
+// caller holds b.mu for reading, so s.ch can't be closed underneath it.
+func (b *Broker[T]) deliver(s *Subscription[T], msg T) (ok, disconnect bool) {
+	select {
+	case s.ch <- msg:
+		return true, false
+	case <-s.done:
+		return false, false
+	default:
+	}
+
+	switch b.opts.SlowSubscriber {
+	case BlockTimeout:
+		t := time.NewTimer(b.opts.Timeout)
+		defer t.Stop()
+		select {
+		case s.ch <- msg:
+			return true, false
+		case <-s.done:
+			return false, false
+		case <-b.quit:
+			return false, false
+		case <-t.C:
+		}
+	case DropNewest:
+	case DropOldest:
+		// Other publishers might be doing the same, and the subscriber
+		// is receiving too, so keep trying until msg fits
+		for {
+			select {
+			case s.ch <- msg:
+				return true, false
+			default:
+			}
+			select {
+			case <-s.ch:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+	case Disconnect:
+		s.leave()
+		s.dropped.Add(1)
+		return false, true
+	default:
+		select {
+		case s.ch <- msg:
+			return true, false
+		case <-s.done:
+			return false, false
+		case <-b.quit:
+			return false, false
+		}
+	}
+	s.dropped.Add(1)
+	return false, false
+}
+
+// Close closes every subscriber's channel. Publishing after Close
+// returns ErrClosed. It's fine to call more than once.
+func (b *Broker[T]) Close() {
+	// Publishers waiting on a full subscriber hold mu for reading, so
+	// they have to be told to give up before mu can be had for writing
+	b.quitOnce.Do(func() { close(b.quit) })
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for _, subs := range b.topics {
+		for s := range subs {
+			s.leave()
+			close(s.ch)
+		}
+	}
+	b.topics = nil
+}
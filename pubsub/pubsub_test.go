@@ -0,0 +1,247 @@
+package pubsub
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// drain receives everything left on sub's channel, which has to be
+// closed already
+func drain(t *testing.T, sub *Subscription[int]) []int {
+	t.Helper()
+	var got []int
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case msg, ok := <-sub.C:
+			if !ok {
+				return got
+			}
+			got = append(got, msg)
+		case <-timeout:
+			t.Fatalf("%s subscriber's channel was never closed", sub.Topic)
+		}
+	}
+}
+
+func TestTopics(t *testing.T) {
+	b := New[int](DefaultOptions)
+	a1, a2, other := b.Subscribe("a"), b.Subscribe("a"), b.Subscribe("b")
+	for i := 1; i <= 3; i++ {
+		if n, err := b.Publish("a", i); n != 2 || err != nil {
+			t.Fatalf("Publish = %d, %v, want 2 subscribers", n, err)
+		}
+	}
+	if n, err := b.Publish("nobody", 0); n != 0 || err != nil {
+		t.Errorf("Publish with no subscribers = %d, %v, want 0, nil", n, err)
+	}
+	b.Close()
+	for _, sub := range []*Subscription[int]{a1, a2} {
+		if got := drain(t, sub); !slices.Equal(got, []int{1, 2, 3}) {
+			t.Errorf("subscriber got %v, want [1 2 3]", got)
+		}
+	}
+	if got := drain(t, other); len(got) != 0 {
+		t.Errorf("other topic got %v", got)
+	}
+}
+
+func TestSlowSubscriberPolicies(t *testing.T) {
+	tests := []struct {
+		policy  Policy
+		want    []int // what the stuck subscriber has waiting
+		dropped uint64
+		closed  bool // whether the stuck subscriber got disconnected
+	}{
+		{BlockTimeout, []int{1, 2}, 3, false},
+		{DropNewest, []int{1, 2}, 3, false},
+		{DropOldest, []int{4, 5}, 3, false},
+		{Disconnect, []int{1, 2}, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			b := New[int](Options{BufferSize: 2, SlowSubscriber: tt.policy, Timeout: time.Millisecond})
+			stuck := b.Subscribe("numbers")
+
+			// A subscriber that keeps up gets everything, whatever the
+			// stuck one is doing
+			fast := b.Subscribe("numbers")
+			for i := 1; i <= 5; i++ {
+				b.Publish("numbers", i)
+				if msg := <-fast.C; msg != i {
+					t.Errorf("fast subscriber got %d, want %d", msg, i)
+				}
+			}
+			if tt.closed {
+				// Already unsubscribed, so its channel is closed with
+				// what it had left in it
+				if left := drain(t, stuck); !slices.Equal(left, tt.want) {
+					t.Errorf("stuck subscriber got %v, want %v", left, tt.want)
+				}
+			}
+			b.Close()
+			if d := fast.Dropped(); d != 0 {
+				t.Errorf("fast subscriber dropped %d", d)
+			}
+			if !tt.closed {
+				if left := drain(t, stuck); !slices.Equal(left, tt.want) {
+					t.Errorf("stuck subscriber got %v, want %v", left, tt.want)
+				}
+			}
+			if d := stuck.Dropped(); d != tt.dropped {
+				t.Errorf("stuck subscriber dropped %d, want %d", d, tt.dropped)
+			}
+		})
+	}
+}
+
+func TestBlockWaitsForSubscriber(t *testing.T) {
+	b := New[int](Options{BufferSize: 1, SlowSubscriber: Block})
+	sub := b.Subscribe("numbers")
+	b.Publish("numbers", 1)
+
+	published := make(chan struct{})
+	go func() {
+		b.Publish("numbers", 2)
+		close(published)
+	}()
+	select {
+	case <-published:
+		t.Fatal("Publish didn't wait for a full subscriber")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if msg := <-sub.C; msg != 1 {
+		t.Errorf("got %d, want 1", msg)
+	}
+	<-published
+	b.Close()
+	if got := drain(t, sub); !slices.Equal(got, []int{2}) {
+		t.Errorf("got %v, want [2]", got)
+	}
+	if d := sub.Dropped(); d != 0 {
+		t.Errorf("Block dropped %d", d)
+	}
+}
+
+func TestUnsubscribeUnblocksPublish(t *testing.T) {
+	b := New[int](Options{BufferSize: 1, SlowSubscriber: Block})
+	defer b.Close()
+	sub := b.Subscribe("numbers")
+	b.Publish("numbers", 1)
+
+	published := make(chan int)
+	go func() {
+		n, _ := b.Publish("numbers", 2)
+		published <- n
+	}()
+	time.Sleep(10 * time.Millisecond)
+	// The publisher is stuck on sub, and unsubscribing has to get it
+	// going again instead of waiting on it
+	sub.Unsubscribe()
+	select {
+	case n := <-published:
+		if n != 0 {
+			t.Errorf("Publish delivered to %d subscribers, want 0", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Publish stayed stuck on an unsubscribed subscriber")
+	}
+	if got := drain(t, sub); !slices.Equal(got, []int{1}) {
+		t.Errorf("got %v, want what was sent before unsubscribing", got)
+	}
+	sub.Unsubscribe()
+}
+
+func TestUnsubscribeRacingPublish(t *testing.T) {
+	for _, p := range []Policy{Block, BlockTimeout, DropNewest, DropOldest, Disconnect} {
+		t.Run(p.String(), func(t *testing.T) {
+			b := New[int](Options{BufferSize: 4, SlowSubscriber: p, Timeout: time.Millisecond})
+			var publishers, subscribers sync.WaitGroup
+			for range 4 {
+				publishers.Add(1)
+				go func() {
+					defer publishers.Done()
+					for i := range 200 {
+						if _, err := b.Publish("numbers", i); err != nil {
+							t.Errorf("Publish: %v", err)
+							return
+						}
+					}
+				}()
+			}
+			// Subscribers come and go while the publishers run. Sending
+			// on a closed channel would panic, and the race detector
+			// catches anything else.
+			for range 8 {
+				subscribers.Add(1)
+				go func() {
+					defer subscribers.Done()
+					for range 20 {
+						sub := b.Subscribe("numbers")
+						for range 3 {
+							select {
+							case <-sub.C:
+							case <-time.After(time.Millisecond):
+							}
+						}
+						sub.Unsubscribe()
+						for range sub.C {
+						}
+					}
+				}()
+			}
+			subscribers.Wait()
+			publishers.Wait()
+			b.Close()
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	b := New[int](DefaultOptions)
+	sub := b.Subscribe("numbers")
+	b.Publish("numbers", 1)
+	b.Close()
+	b.Close()
+
+	if n, err := b.Publish("numbers", 2); n != 0 || !errors.Is(err, ErrClosed) {
+		t.Errorf("Publish after Close = %d, %v, want 0, %v", n, err, ErrClosed)
+	}
+	if got := drain(t, sub); !slices.Equal(got, []int{1}) {
+		t.Errorf("got %v, want [1]", got)
+	}
+	// Subscribing afterwards gives a closed channel, so ranging over it
+	// ends right away
+	if got := drain(t, b.Subscribe("numbers")); len(got) != 0 {
+		t.Errorf("subscribing after Close got %v", got)
+	}
+	sub.Unsubscribe()
+}
+
+func TestCloseUnblocksPublish(t *testing.T) {
+	b := New[int](Options{BufferSize: 1, SlowSubscriber: Block})
+	sub := b.Subscribe("numbers")
+	b.Publish("numbers", 1)
+
+	// The publisher gets stuck on sub, which never reads, while holding
+	// the broker's lock for reading
+	go b.Publish("numbers", 2)
+	time.Sleep(10 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		b.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close waited on a publisher stuck on a full subscriber")
+	}
+	if got := drain(t, sub); !slices.Equal(got, []int{1}) {
+		t.Errorf("got %v, want [1]", got)
+	}
+}
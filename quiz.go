@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+// quizCommand asks the learner to predict what lessons print. Each
+// question shows a lesson's code, with the comments taken out since
+// they tend to give the answer away, then runs the lesson and scores
+// the guess against what it really printed.
+func quizCommand(args []string) error {
+	fs := newFlagSet("quiz", "[lessons...]")
+	f := filterFlags(fs)
+	opts := runnerFlags(fs)
+	o := learnerFlags(fs)
+	typed := fs.Bool("type", false, "type every answer, even when there are choices")
+	history := fs.Bool("history", false, "show past quiz scores instead of asking questions")
+	ls, err := selectLessons(fs, f, args)
+	if err != nil {
+		return err
+	}
+	if *history {
+		return quizHistory(o)
+	}
+
+	var quizzes []lessons.Lesson
+	for _, l := range ls {
+		if l.Quiz != nil {
+			quizzes = append(quizzes, l)
+		}
+	}
+	if len(quizzes) == 0 {
+		return fmt.Errorf("none of those lessons have a quiz")
+	}
+
+	in := bufio.NewScanner(os.Stdin)
+	right := 0
+	for i, l := range quizzes {
+		fmt.Printf("Question %d of %d: %s (%s)\n\n", i+1, len(quizzes), l.Title, l.ID)
+		correct, ok, err := askQuiz(in, l, *opts, *typed)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			// Out of input, so stop without counting this one
+			break
+		}
+		if correct {
+			right++
+		}
+		// Not being able to save an answer shouldn't end the quiz
+		if err := recordAnswer(o, l.ID, correct); err != nil {
+			fmt.Fprintln(os.Stderr, "hellogo:", err)
+		}
+		fmt.Println(lessons.Separator)
+	}
+
+	// The all-time score is left off if there's no progress to read it
+	// from, rather than failing a quiz that's already over
+	var allTime string
+	if !o.off {
+		if _, learner, err := o.load(); err != nil {
+			fmt.Fprintln(os.Stderr, "hellogo:", err)
+		} else {
+			allRight, allTotal := learner.Score()
+			allTime = fmt.Sprintf(" All time: %d of %d.", allRight, allTotal)
+		}
+	}
+	fmt.Printf("You got %d of %d right.%s\n", right, len(quizzes), allTime)
+	return nil
+}
+
+// askQuiz asks one question. ok is false if stdin ran out before the
+// learner answered.
+func askQuiz(in *bufio.Scanner, l lessons.Lesson, opts lessons.Options, typed bool) (correct, ok bool, err error) {
+	// The lesson runs first, out of sight, since the choices are built
+	// from what it really prints
+	var out bytes.Buffer
+	err = lessons.Capture(&out, func() {
+		lessons.RunLesson(l, opts)
+	})
+	if err != nil {
+		return false, false, err
+	}
+	printed := lessons.Normalize(l, out.String())
+	answer := tail(printed, l.Quiz.Tail)
+
+	code, err := lessons.Source(l)
+	if err != nil {
+		return false, false, err
+	}
+	fmt.Println(stripComments(code.Func))
+	fmt.Println()
+	question := l.Quiz.Question
+	if question == "" {
+		question = "What does this print?"
+	}
+	fmt.Println(question)
+
+	var guess string
+	choices := quizChoices(answer, l.Quiz.Distractors)
+	if typed || len(choices) < 2 {
+		fmt.Println("Type the output, then an empty line:")
+		var lines []string
+		for {
+			if !in.Scan() {
+				if len(lines) == 0 {
+					return false, false, nil
+				}
+				break
+			}
+			if in.Text() == "" {
+				break
+			}
+			lines = append(lines, in.Text())
+		}
+		guess = strings.Join(lines, "\n")
+	} else {
+		for i, c := range choices {
+			fmt.Printf("\n  %d)\n", i+1)
+			for _, line := range strings.Split(c, "\n") {
+				fmt.Printf("     %s\n", line)
+			}
+		}
+		for guess == "" {
+			fmt.Printf("\nPick 1-%d: ", len(choices))
+			if !in.Scan() {
+				fmt.Println()
+				return false, false, nil
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(in.Text()))
+			if err == nil && n >= 1 && n <= len(choices) {
+				guess = choices[n-1]
+			}
+		}
+	}
+
+	correct = sameOutput(guess, answer)
+	if correct {
+		fmt.Println("\nCorrect! It prints:")
+	} else {
+		fmt.Println("\nNot quite. It really prints:")
+	}
+	fmt.Print(out.String())
+	return correct, true, nil
+}
+
+// quizChoices mixes the real answer in with the distractors, leaving
+// out any distractor that turns out to be the real answer
+func quizChoices(answer string, distractors []string) []string {
+	choices := []string{answer}
+	for _, d := range distractors {
+		if !sameOutput(d, answer) {
+			choices = append(choices, d)
+		}
+	}
+	rand.Shuffle(len(choices), func(i, j int) {
+		choices[i], choices[j] = choices[j], choices[i]
+	})
+	return choices
+}
+
+// sameOutput compares two outputs without caring about blank lines or
+// spaces around lines, which are easy to get wrong when typing
+func sameOutput(a, b string) bool {
+	return strings.Join(outputLines(a), "\n") == strings.Join(outputLines(b), "\n")
+}
+
+func outputLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// tail returns the last n lines of out, or all of it if n is zero
+func tail(out string, n int) string {
+	lines := outputLines(out)
+	if n > 0 && n < len(lines) {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stripComments reprints a function without its comments. If it can't
+// be parsed, it's returned as is.
+func stripComments(src string) string {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", "package quiz\n"+src, 0)
+	if err != nil || len(f.Decls) == 0 {
+		return src
+	}
+	var b bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := cfg.Fprint(&b, fset, f.Decls[0].(*ast.FuncDecl)); err != nil {
+		return src
+	}
+	return b.String()
+}
+
+func recordAnswer(o *learnerOptions, id string, correct bool) error {
+	if o.off {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	state, learner, err := o.load()
+	if err != nil {
+		return err
+	}
+	learner.RecordAnswer(id, correct, time.Now())
+	return o.save(state)
+}
+
+// quizHistory prints every quiz question the learner has answered,
+// grouped by lesson
+func quizHistory(o *learnerOptions) error {
+	_, learner, err := o.load()
+	if err != nil {
+		return err
+	}
+	if len(learner.Quiz) == 0 {
+		fmt.Printf("%s hasn't answered any quiz questions yet.\n", o.user)
+		return nil
+	}
+
+	type tally struct{ right, total int }
+	tallies := map[string]*tally{}
+	for _, a := range learner.Quiz {
+		t, ok := tallies[a.Lesson]
+		if !ok {
+			t = &tally{}
+			tallies[a.Lesson] = t
+		}
+		t.total++
+		if a.Correct {
+			t.right++
+		}
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Quiz history for %s\n\n", o.user)
+	for _, l := range lessons.All() {
+		if t, ok := tallies[l.ID]; ok {
+			fmt.Fprintf(w, "%s\t%d/%d\n", l.ID, t.right, t.total)
+		}
+	}
+	right, total := learner.Score()
+	last := learner.Quiz[len(learner.Quiz)-1]
+	fmt.Fprintf(w, "\ntotal\t%d/%d\n", right, total)
+	fmt.Fprintf(w, "last answer\t%s\n", last.At.Format("2006-01-02 15:04"))
+	return w.Flush()
+}
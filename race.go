@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+// raceCommand builds a copy of hellogo with the race detector turned on
+// and runs lessons with it, one process per lesson. Every data race the
+// detector reports is shown against the lesson's source, with the lines
+// that conflicted marked. Lessons that name a FixedBy lesson get that
+// one run too, and it has to come out clean.
+func raceCommand(args []string) error {
+	fs := newFlagSet("race", "[lessons...]")
+	f := filterFlags(fs)
+	timeout := fs.Duration("timeout", lessons.DefaultOptions.Timeout, "how long each lesson gets")
+	ls, err := selectLessons(fs, f, args)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "hellogo-race-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "hellogo-race")
+	fmt.Println("Building with -race...")
+	build := exec.Command("go", "build", "-race", "-o", bin, ".")
+	build.Dir = lessons.RepoRoot()
+	build.Stdout, build.Stderr = os.Stdout, os.Stderr
+	if err := build.Run(); err != nil {
+		return fmt.Errorf("building with -race: %w", err)
+	}
+
+	var broken []string
+	for _, l := range ls {
+		races, err := raceRun(bin, l, *timeout)
+		if err != nil {
+			return err
+		}
+		printRaces(l, races)
+		if l.FixedBy == "" {
+			continue
+		}
+		fixed, ok := lessons.Lookup(l.FixedBy)
+		if !ok {
+			return fmt.Errorf("%s is fixed by %q, which isn't a lesson", l.ID, l.FixedBy)
+		}
+		races, err = raceRun(bin, fixed, *timeout)
+		if err != nil {
+			return err
+		}
+		if len(races) == 0 {
+			fmt.Printf("ok   the fixed version, %s, runs clean\n", fixed.ID)
+		} else {
+			printRaces(fixed, races)
+			broken = append(broken, fixed.ID)
+		}
+		fmt.Println(lessons.Separator)
+	}
+	if len(broken) > 0 {
+		return fmt.Errorf("fixed lessons still race: %s", strings.Join(broken, ", "))
+	}
+	return nil
+}
+
+// A raceAccess is one side of a data race: a read or a write, and the
+// stack it happened on
+type raceAccess struct {
+	Write     bool
+	Goroutine string
+	Frames    []lessons.Frame
+}
+
+type race struct {
+	Accesses []raceAccess
+}
+
+// raceRun runs a single lesson with the race detector build and returns
+// the races it reported
+func raceRun(bin string, l lessons.Lesson, timeout time.Duration) ([]race, error) {
+	cmd := exec.Command(bin, "run", "--raw", "--timeout", timeout.String(), l.ID)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, fmt.Errorf("running %s: %w", l.ID, err)
+	}
+	return parseRaces(stderr.String()), nil
+}
+
+var raceAccessHeader = regexp.MustCompile(`(?i)^(?:previous )?(read|write|atomic read|atomic write)(?: of size \d+)? at 0x[0-9a-f]+ by (.+):$`)
+
+// parseRaces pulls the data race reports out of what a -race binary
+// printed to stderr. Each report sits between two lines of "=" and
+// starts with "WARNING: DATA RACE".
+func parseRaces(stderr string) []race {
+	var races []race
+	var cur *race
+	var access *raceAccess
+	scanner := bufio.NewScanner(strings.NewReader(stderr))
+	var funcLine string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "WARNING: DATA RACE":
+			races = append(races, race{})
+			cur, access = &races[len(races)-1], nil
+		case cur == nil:
+		case line == "":
+			access = nil
+		case strings.HasPrefix(line, "=================="):
+			cur, access = nil, nil
+		case !strings.HasPrefix(line, " "):
+			// A section header. Only the read and write sections are
+			// interesting, not where the goroutines were created.
+			access = nil
+			if m := raceAccessHeader.FindStringSubmatch(line); m != nil {
+				cur.Accesses = append(cur.Accesses, raceAccess{
+					Write:     strings.HasSuffix(strings.ToLower(m[1]), "write"),
+					Goroutine: m[2],
+				})
+				access = &cur.Accesses[len(cur.Accesses)-1]
+			}
+		case access == nil:
+		case strings.HasPrefix(line, "      "):
+			// The file:line half of a frame
+			loc, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+			file, num := loc, 0
+			if i := strings.LastIndex(loc, ":"); i > 0 {
+				file = loc[:i]
+				num, _ = strconv.Atoi(loc[i+1:])
+			}
+			access.Frames = append(access.Frames, lessons.Frame{Func: funcLine, File: file, Line: num})
+		default:
+			funcLine = strings.TrimSuffix(strings.TrimSpace(line), "()")
+		}
+	}
+	return races
+}
+
+func printRaces(l lessons.Lesson, races []race) {
+	if len(races) == 0 {
+		fmt.Printf("ok   %s has no data races\n", l.ID)
+		return
+	}
+	code, err := lessons.Source(l)
+	for i, r := range races {
+		fmt.Printf("DATA RACE %d of %d in %s\n", i+1, len(races), l.ID)
+		marks := map[int]string{}
+		for _, a := range r.Accesses {
+			kind := "read "
+			if a.Write {
+				kind = "write"
+			}
+			if len(a.Frames) == 0 {
+				continue
+			}
+			top := a.Frames[0]
+			fmt.Printf("  %s by %s at %s:%d in %s\n", kind, a.Goroutine, lessons.RepoPath(top.File), top.Line, shortFunc(top.Func))
+			// Mark the innermost call inside the lesson's own code,
+			// which is the top frame unless the race is in a helper
+			if err != nil {
+				continue
+			}
+			for _, f := range a.Frames {
+				if f.File == code.File {
+					marks[f.Line] = strings.ToUpper(kind[:1])
+					break
+				}
+			}
+		}
+		if err == nil && len(marks) > 0 {
+			fmt.Println()
+			printMarkedSource(code, marks)
+		}
+		fmt.Println()
+	}
+}
+
+// printMarkedSource prints the part of a lesson's source around the
+// marked lines, with line numbers and the marks in the margin
+func printMarkedSource(code lessons.Code, marks map[int]string) {
+	lines := strings.Split(code.Text, "\n")
+	first, last := -1, -1
+	for i := range lines {
+		if _, ok := marks[code.Line+i]; ok {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return
+	}
+	// A few lines either side, to show what the marked lines are part of
+	const context = 3
+	first, last = max(0, first-context), min(len(lines)-1, last+context)
+	for i := first; i <= last; i++ {
+		n := code.Line + i
+		margin := "  "
+		if m, ok := marks[n]; ok {
+			margin = m + ">"
+		}
+		fmt.Printf("  %s %4d | %s\n", margin, n, lines[i])
+	}
+}
+
+// shortFunc drops the module path from a function name, so
+// github.com/nicolasjhampton/hellogo/goroutines.goroutineCreation.func2
+// becomes goroutines.goroutineCreation.func2
+func shortFunc(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
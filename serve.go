@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+	"github.com/nicolasjhampton/hellogo/playground"
+)
+
+// serveCommand starts the playground, a local web page for browsing
+// lessons and running them
+func serveCommand(args []string) error {
+	fs := newFlagSet("serve", "")
+	opts := runnerFlags(fs)
+	o := learnerFlags(fs)
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	s := newPlayground(*opts, o)
+	// Listening before printing the address means the address is
+	// right even with a port of 0
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Serving the playground at http://%s/\n", ln.Addr())
+	return http.Serve(ln, s)
+}
+
+// newPlayground makes a playground that records the learner's progress
+// from every lesson it runs
+func newPlayground(opts lessons.Options, o *learnerOptions) *playground.Server {
+	s := playground.New(opts)
+	s.OnResult = func(res lessons.Result) {
+		if err := o.record(res); err != nil {
+			log.Printf("recording progress: %v", err)
+		}
+	}
+	return s
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+	"github.com/nicolasjhampton/hellogo/progress"
+)
+
+func TestServeRecordsEveryRun(t *testing.T) {
+	o := &learnerOptions{path: filepath.Join(t.TempDir(), "progress.json"), user: "test"}
+	srv := httptest.NewServer(newPlayground(lessons.DefaultOptions, o))
+	defer srv.Close()
+
+	// Every run reads the progress file and writes it back, so runs
+	// finishing together mustn't lose each other's updates
+	const runs = 8
+	l := lessons.All()[0]
+	var wg sync.WaitGroup
+	for range runs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := srv.Client().Get(srv.URL + "/run/" + l.ID)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	state, err := progress.Load(o.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.Learner("test").Lessons[l.ID].Runs; got != runs {
+		t.Errorf("recorded %d runs of %s, want %d", got, l.ID, runs)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+const stepHelp = `  Enter  run the lesson, or go on to the next one once it's run
+  r      run the lesson again
+  s      skip to the next lesson
+  b      go back to the previous lesson
+  c NAME jump to the start of a chapter
+  l      list the chapters
+  q      quit`
+
+// stepCommand walks through lessons one at a time. Before each lesson it
+// shows the title and source and waits, so there's time to read the
+// code and guess what it'll print before running it.
+func stepCommand(args []string) error {
+	fs := newFlagSet("step", "[lessons...]")
+	f := filterFlags(fs)
+	opts := runnerFlags(fs)
+	o := learnerFlags(fs)
+	ls, err := selectLessons(fs, f, args)
+	if err != nil {
+		return err
+	}
+	return runStepper(ls, *opts, o)
+}
+
+// runStepper steps through ls, recording each lesson that gets run in
+// the learner's progress
+func runStepper(ls []lessons.Lesson, opts lessons.Options, o *learnerOptions) error {
+	if len(ls) == 0 {
+		return nil
+	}
+	warnAhead(os.Stderr, ls, o)
+	s := stepper{lessons: ls, opts: opts, learner: o, in: bufio.NewScanner(os.Stdin), out: os.Stdout}
+	s.loop()
+	return nil
+}
+
+type stepper struct {
+	lessons []lessons.Lesson
+	opts    lessons.Options
+	learner *learnerOptions
+	in      *bufio.Scanner
+	out     io.Writer
+}
+
+func (s *stepper) loop() {
+	i, ran := 0, false
+	show := true
+	for i < len(s.lessons) {
+		l := s.lessons[i]
+		if show {
+			c, _ := lessons.LookupChapter(l.Chapter)
+			fmt.Fprintln(s.out, lessons.Banner(c.Title))
+			fmt.Fprintf(s.out, "Lesson %d of %d\n", i+1, len(s.lessons))
+			lessons.PrintSource(s.out, l)
+			show, ran = false, false
+		}
+
+		prompt := "[Enter] run"
+		if ran {
+			prompt = "[Enter] next"
+		}
+		fmt.Fprintf(s.out, "%s  r replay  s skip  b back  c chapter  l list  ? help  q quit > ", prompt)
+		if !s.in.Scan() {
+			fmt.Fprintln(s.out)
+			return
+		}
+		cmd, arg, _ := strings.Cut(strings.TrimSpace(s.in.Text()), " ")
+
+		switch cmd {
+		case "":
+			if ran {
+				i, show = i+1, true
+				continue
+			}
+			s.run(l)
+			ran = true
+		case "r":
+			s.run(l)
+			ran = true
+		case "s":
+			i, show = i+1, true
+		case "b":
+			if i > 0 {
+				i--
+			}
+			show = true
+		case "c":
+			if j := s.chapterStart(strings.TrimSpace(arg)); j >= 0 {
+				i, show = j, true
+			}
+		case "l":
+			s.listChapters()
+		case "q":
+			return
+		default:
+			fmt.Fprintln(s.out, stepHelp)
+		}
+	}
+	fmt.Fprintln(s.out, "That's the last lesson.")
+}
+
+func (s *stepper) run(l lessons.Lesson) {
+	fmt.Fprintln(s.out, "Output:")
+	res := lessons.RunLesson(l, s.opts)
+	lessons.Report(s.out, res)
+	fmt.Fprintln(s.out, lessons.Separator)
+	// Saved after every lesson, so quitting halfway keeps what was done
+	if err := s.learner.record(res); err != nil {
+		fmt.Fprintln(os.Stderr, "hellogo:", err)
+	}
+}
+
+// chapterStart finds the first lesson in a chapter, or prints why it
+// can't and returns -1
+func (s *stepper) chapterStart(name string) int {
+	for j, l := range s.lessons {
+		if l.Chapter == name {
+			return j
+		}
+	}
+	if name == "" {
+		fmt.Fprintln(s.out, "Which chapter? Try l to list them.")
+	} else {
+		fmt.Fprintf(s.out, "No lessons from a chapter named %q here. Try l to list them.\n", name)
+	}
+	return -1
+}
+
+func (s *stepper) listChapters() {
+	seen := map[string]bool{}
+	for _, l := range s.lessons {
+		if !seen[l.Chapter] {
+			seen[l.Chapter] = true
+			fmt.Fprintf(s.out, "  %s\n", l.Chapter)
+		}
+	}
+}
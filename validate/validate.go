@@ -0,0 +1,463 @@
+// Package validate checks structs against rules written in their field
+// tags, like the Animal struct from the maps and structs chapter:
+//
+//	type Animal struct {
+//		Name   string `required max:"100"`
+//		Origin string `oneof:"Africa Australia Asia"`
+//	}
+//
+// The rules are:
+//
+//	required      the field can't be its zero value, or empty, and
+//	              pointers can't be nil
+//	min:"n"       numbers can't be less than n, and strings, slices and
+//	              maps can't be shorter than n
+//	max:"n"       the same, but for the most
+//	len:"n"       strings, slices and maps must be exactly n long
+//	regex:"re"    strings must match the regular expression re
+//	oneof:"a b"   the value must be one of the space separated options
+//
+// Once a field fails required, its other rules are skipped, since
+// they'd only be complaining about the same missing value.
+//
+// Rules can be bare words or key:"value" pairs, so `required` and
+// `required:"true"` mean the same thing. go vet only likes the second
+// form, since it's what reflect.StructTag.Get understands. Tags for
+// other packages, like json, are left alone, even if they're malformed.
+//
+// Validate goes into embedded and nested structs, pointers, and the
+// elements of slices, arrays and maps, and reports every problem it
+// finds, not just the first, each with the path to the field.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// A FieldError is one field breaking one rule
+type FieldError struct {
+	// Path leads to the field from the struct given to Validate, like
+	// "Animal.Name" or "Flock[2].Origin"
+	Path string
+	// Rule is the tag key that was broken, like "max"
+	Rule string
+	// Param is the rule's value from the tag, like "100"
+	Param string
+	// Value is what the field held
+	Value any
+}
+
+func (e *FieldError) Error() string {
+	return e.Path + ": " + e.message()
+}
+
+func (e *FieldError) message() string {
+	switch e.Rule {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be at least " + e.Param
+	case "max":
+		return "must be at most " + e.Param
+	case "len":
+		return "must be exactly " + e.Param + " long"
+	case "regex":
+		return fmt.Sprintf("%q doesn't match %s", e.Value, e.Param)
+	case "oneof":
+		return fmt.Sprintf("%v must be one of %s", e.Value, e.Param)
+	}
+	return "breaks the " + e.Rule + " rule"
+}
+
+// Errors is every FieldError Validate found, in field order
+type Errors []*FieldError
+
+func (es Errors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap lets errors.As find the individual FieldErrors
+func (es Errors) Unwrap() []error {
+	errs := make([]error, len(es))
+	for i, e := range es {
+		errs[i] = e
+	}
+	return errs
+}
+
+// A TagError is a tag Validate couldn't make sense of, like max:"lots".
+// It's a mistake in the struct, not the data, so Validate gives up when
+// it finds one instead of adding it to the Errors.
+type TagError struct {
+	Path string
+	Rule string
+	Err  error
+}
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("validate: bad %s tag on %s: %v", e.Rule, e.Path, e.Err)
+}
+
+func (e *TagError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks v, which should be a struct or a pointer to one. It
+// returns nil if everything passes, Errors if some fields don't, or a
+// TagError if a tag is malformed.
+func Validate(v any) error {
+	w := walker{seen: map[uintptr]bool{}}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return errors.New("validate: nil pointer")
+		}
+		w.seen[rv.Pointer()] = true
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("validate: can't validate a %s, only structs", rv.Kind())
+	}
+
+	if err := w.walkStruct("", rv); err != nil {
+		return err
+	}
+	if len(w.errs) > 0 {
+		return w.errs
+	}
+	return nil
+}
+
+type walker struct {
+	errs Errors
+	// seen holds the pointers already followed, so a struct that
+	// points back at itself doesn't send us around forever
+	seen map[uintptr]bool
+}
+
+func (w *walker) walkStruct(path string, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		// Unexported fields are skipped, except for embedded structs,
+		// whose exported fields get promoted
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		fieldPath := f.Name
+		if path != "" {
+			fieldPath = path + "." + f.Name
+		}
+		rules, err := parseTag(f.Tag)
+		if err != nil {
+			return &TagError{Path: fieldPath, Rule: "struct", Err: err}
+		}
+		if err := w.walkValue(fieldPath, v.Field(i), rules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkValue checks v against its rules and then looks inside it
+func (w *walker) walkValue(path string, v reflect.Value, rules []rule) error {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			// Only required has anything to say about a nil
+			if hasRule(rules, "required") {
+				w.fail(path, rule{key: "required"}, reflect.Value{})
+			}
+			return nil
+		}
+		if v.Kind() == reflect.Pointer {
+			if w.seen[v.Pointer()] {
+				return nil
+			}
+			w.seen[v.Pointer()] = true
+		}
+		// A pointer or interface that isn't nil is all required asks
+		// for. What it points at can be a zero value.
+		rules = without(rules, "required")
+		v = v.Elem()
+	}
+
+	for _, r := range rules {
+		ok, err := r.check(v)
+		if err != nil {
+			return &TagError{Path: path, Rule: r.key, Err: err}
+		}
+		if !ok {
+			w.fail(path, r, v)
+			if r.key == "required" {
+				// The other rules would only pile on
+				break
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return w.walkStruct(path, v)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := w.walkValue(fmt.Sprintf("%s[%d]", path, i), v.Index(i), nil); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		// Keys are sorted so the errors come out in the same order
+		// every time
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		for _, k := range keys {
+			if err := w.walkValue(fmt.Sprintf("%s[%v]", path, k), v.MapIndex(k), nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *walker) fail(path string, r rule, v reflect.Value) {
+	e := &FieldError{Path: path, Rule: r.key, Param: r.param}
+	if v.IsValid() && v.CanInterface() {
+		e.Value = v.Interface()
+	}
+	w.errs = append(w.errs, e)
+}
+
+// A rule is one key from a tag, with its value if it had one
+type rule struct {
+	key   string
+	param string
+}
+
+var ruleKeys = map[string]bool{
+	"required": true,
+	"min":      true,
+	"max":      true,
+	"len":      true,
+	"regex":    true,
+	"oneof":    true,
+}
+
+// parseTag pulls the rules out of a tag. It works like
+// reflect.StructTag.Lookup, except that keys don't need a value. Only
+// the keys in ruleKeys are checked for mistakes. Anything else in the
+// tag belongs to some other package, so it's skipped over as loosely
+// as possible.
+func parseTag(tag reflect.StructTag) ([]rule, error) {
+	var rules []rule
+	s := string(tag)
+	for {
+		s = strings.TrimLeft(s, " ")
+		if s == "" {
+			return rules, nil
+		}
+		i := 0
+		for i < len(s) && s[i] > ' ' && s[i] != ':' && s[i] != '"' {
+			i++
+		}
+		r := rule{key: s[:i]}
+		ours := ruleKeys[r.key]
+		s = s[i:]
+		switch {
+		case strings.HasPrefix(s, `:"`):
+			// Find the closing quote, skipping escaped ones
+			j := 2
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				if !ours {
+					return rules, nil
+				}
+				return nil, fmt.Errorf("unterminated value for %s in tag %q", r.key, tag)
+			}
+			quoted := s[1 : j+1]
+			s = s[j+1:]
+			if !ours {
+				continue
+			}
+			param, err := strconv.Unquote(quoted)
+			if err != nil {
+				return nil, fmt.Errorf("bad value for %s in tag %q: %w", r.key, tag, err)
+			}
+			r.param = param
+		case i == 0 || strings.HasPrefix(s, ":"):
+			// Not a key or not a quoted value, so skip to the next space
+			if ours {
+				return nil, fmt.Errorf("value for %s in tag %q should be quoted", r.key, tag)
+			}
+			if k := strings.IndexByte(s, ' '); k >= 0 {
+				s = s[k:]
+			} else {
+				s = ""
+			}
+			continue
+		}
+		if !ours {
+			continue
+		}
+		if r.key == "required" && r.param != "" {
+			required, err := strconv.ParseBool(r.param)
+			if err != nil {
+				return nil, fmt.Errorf("required should be true or false, not %q", r.param)
+			}
+			if !required {
+				continue
+			}
+		}
+		rules = append(rules, r)
+	}
+}
+
+// without returns rules minus any with key
+func without(rules []rule, key string) []rule {
+	var rest []rule
+	for _, r := range rules {
+		if r.key != key {
+			rest = append(rest, r)
+		}
+	}
+	return rest
+}
+
+func hasRule(rules []rule, key string) bool {
+	for _, r := range rules {
+		if r.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// check reports whether v follows the rule. The error is for rules
+// that don't make sense, like a max that isn't a number, or a regex on
+// something that isn't a string.
+func (r rule) check(v reflect.Value) (bool, error) {
+	switch r.key {
+	case "required":
+		switch v.Kind() {
+		case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+			return v.Len() > 0, nil
+		}
+		return !v.IsZero(), nil
+	case "min", "max":
+		n, err := strconv.ParseFloat(r.param, 64)
+		if err != nil {
+			return false, err
+		}
+		size, err := size(v)
+		if err != nil {
+			return false, err
+		}
+		if r.key == "min" {
+			return size >= n, nil
+		}
+		return size <= n, nil
+	case "len":
+		n, err := strconv.Atoi(r.param)
+		if err != nil {
+			return false, err
+		}
+		switch v.Kind() {
+		case reflect.String:
+			return utf8.RuneCountInString(v.String()) == n, nil
+		case reflect.Slice, reflect.Map, reflect.Array:
+			return v.Len() == n, nil
+		}
+		return false, fmt.Errorf("a %s has no length", v.Kind())
+	case "regex":
+		if v.Kind() != reflect.String {
+			return false, fmt.Errorf("can't match a %s against a regex", v.Kind())
+		}
+		re, err := compile(r.param)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(v.String()), nil
+	case "oneof":
+		s := text(v)
+		for _, option := range strings.Fields(r.param) {
+			if s == option {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// text formats v for oneof to compare. It avoids Interface where it
+// can, since that panics on fields reached through unexported embedded
+// structs.
+func text(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	}
+	if v.CanInterface() {
+		return fmt.Sprint(v.Interface())
+	}
+	return v.Type().String()
+}
+
+// size is what min and max compare against: the value of a number, or
+// the length of anything else
+func size(v reflect.Value) (float64, error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), nil
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), nil
+	}
+	return 0, fmt.Errorf("a %s has no size", v.Kind())
+}
+
+// Regular expressions get compiled once and reused, since the same
+// struct usually gets validated over and over
+var regexps sync.Map
+
+func compile(expr string) (*regexp.Regexp, error) {
+	if re, ok := regexps.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	regexps.Store(expr, re)
+	return re, nil
+}
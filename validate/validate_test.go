@@ -0,0 +1,217 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// paths returns the path and rule of each FieldError in err
+func paths(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var es Errors
+	if !errors.As(err, &es) {
+		t.Fatalf("got %T %v, want Errors", err, err)
+	}
+	var ps []string
+	for _, e := range es {
+		ps = append(ps, e.Path+" "+e.Rule)
+	}
+	return ps
+}
+
+func check(t *testing.T, v any, want ...string) {
+	t.Helper()
+	got := paths(t, Validate(v))
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("Validate(%+v) failed %q, want %q", v, got, want)
+	}
+}
+
+// withTag builds a struct with one field of type typ and the given tag.
+// go vet rejects tags like these written out in the source, which is
+// the point of some of them.
+func withTag(tag string, typ reflect.Type, value any) any {
+	t := reflect.StructOf([]reflect.StructField{{Name: "F", Type: typ, Tag: reflect.StructTag(tag)}})
+	v := reflect.New(t).Elem()
+	if value != nil {
+		v.Field(0).Set(reflect.ValueOf(value))
+	}
+	return v.Interface()
+}
+
+func TestRules(t *testing.T) {
+	type Rules struct {
+		Name  string   `required:"true"`
+		Age   int      `min:"0" max:"150"`
+		Tags  []string `min:"1" max:"2"`
+		Code  string   `len:"3"`
+		Email string   `regex:"^[^@]+@[^@]+$"`
+		Color string   `oneof:"red green blue"`
+		Level int      `oneof:"1 2 3"`
+	}
+	ok := Rules{Name: "Emu", Age: 30, Tags: []string{"bird"}, Code: "EMU", Email: "emu@example.com", Color: "red", Level: 2}
+	check(t, ok)
+	check(t, &ok)
+
+	tests := []struct {
+		name   string
+		change func(r *Rules)
+		want   string
+	}{
+		{"required", func(r *Rules) { r.Name = "" }, "Name required"},
+		{"min number", func(r *Rules) { r.Age = -1 }, "Age min"},
+		{"max number", func(r *Rules) { r.Age = 151 }, "Age max"},
+		{"min length", func(r *Rules) { r.Tags = nil }, "Tags min"},
+		{"max length", func(r *Rules) { r.Tags = []string{"a", "b", "c"} }, "Tags max"},
+		{"len", func(r *Rules) { r.Code = "EMUS" }, "Code len"},
+		{"len counts runes", func(r *Rules) { r.Code = "ÉMÜ" }, ""},
+		{"regex", func(r *Rules) { r.Email = "emu" }, "Email regex"},
+		{"oneof", func(r *Rules) { r.Color = "purple" }, "Color oneof"},
+		{"oneof number", func(r *Rules) { r.Level = 4 }, "Level oneof"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := ok
+			tt.change(&r)
+			if tt.want == "" {
+				check(t, r)
+			} else {
+				check(t, r, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequired(t *testing.T) {
+	type Required struct {
+		S   string         `required:"true"`
+		N   int            `required:"true"`
+		P   *int           `required:"true"`
+		M   map[string]int `required:"true" min:"2"`
+		Off string         `required:"false" max:"2"`
+	}
+	// Failing required skips the field's other rules, and
+	// required:"false" turns the rule off without turning off the rest
+	check(t, Required{Off: "long"}, "S required", "N required", "P required", "M required", "Off max")
+	n := 0
+	check(t, Required{S: "s", N: 1, P: &n, M: map[string]int{"a": 1, "b": 2}})
+}
+
+func TestBareKeys(t *testing.T) {
+	// A key without a value works like key:"true"
+	check(t, withTag(`required max:"3"`, reflect.TypeOf(""), nil), "F required")
+	check(t, withTag(`required max:"3"`, reflect.TypeOf(""), "long"), "F max")
+	check(t, withTag(`required max:"3"`, reflect.TypeOf(""), "ok"))
+}
+
+func TestOtherTags(t *testing.T) {
+	str := reflect.TypeOf("")
+	// Other packages' keys are none of our business, even when they
+	// aren't well formed
+	for _, tag := range []string{
+		`json:"name,omitempty" max:"3"`,
+		`json:x max:"3"`,
+		`max:"3" json:x`,
+		`"stray" max:"3"`,
+		`max:"3" json:"unterminated`,
+	} {
+		t.Run(tag, func(t *testing.T) {
+			check(t, withTag(tag, str, "ok"))
+			check(t, withTag(tag, str, "long"), "F max")
+		})
+	}
+}
+
+func TestTagErrors(t *testing.T) {
+	str := reflect.TypeOf("")
+	for _, tag := range []string{
+		`max:"lots"`,
+		`max:3`,
+		`max:"3`,
+		`regex:"["`,
+		`required:"maybe"`,
+		`len:"3"`, // on an int, which has no length
+	} {
+		t.Run(tag, func(t *testing.T) {
+			typ := str
+			if strings.HasPrefix(tag, "len") {
+				typ = reflect.TypeOf(0)
+			}
+			var te *TagError
+			if err := Validate(withTag(tag, typ, nil)); !errors.As(err, &te) {
+				t.Errorf("Validate = %v, want a TagError", err)
+			}
+		})
+	}
+}
+
+type animal struct {
+	Name   string `required:"true"`
+	Origin string `oneof:"Africa Australia"`
+}
+
+type bird struct {
+	animal
+	Wings int `min:"2"`
+}
+
+type aviary struct {
+	Flock  []bird
+	ByName map[string]*bird
+	Keeper struct {
+		Name string `required:"true"`
+	}
+}
+
+func TestNestedPaths(t *testing.T) {
+	a := aviary{
+		Flock: []bird{
+			{animal: animal{Name: "Emu", Origin: "Australia"}, Wings: 2},
+			{animal: animal{Origin: "Europe"}, Wings: 1},
+		},
+		ByName: map[string]*bird{
+			"kiwi":    {animal: animal{Name: "Kiwi", Origin: "Asia"}, Wings: 2},
+			"ostrich": {animal: animal{Name: "Ostrich", Origin: "Africa"}, Wings: 2},
+			"missing": nil,
+		},
+	}
+	check(t, a,
+		"Flock[1].animal.Name required",
+		"Flock[1].animal.Origin oneof",
+		"Flock[1].Wings min",
+		"ByName[kiwi].animal.Origin oneof",
+		"Keeper.Name required",
+	)
+}
+
+type node struct {
+	Name string `required:"true"`
+	Next *node
+}
+
+func TestCycles(t *testing.T) {
+	a := &node{Name: "a"}
+	b := &node{Next: a}
+	a.Next = b
+	// Each node is only checked once, however the loop is entered
+	check(t, a, "Next.Name required")
+	check(t, b, "Name required")
+
+	self := &node{}
+	self.Next = self
+	check(t, self, "Name required")
+}
+
+func TestNotAStruct(t *testing.T) {
+	var p *node
+	for _, v := range []any{nil, 42, p} {
+		if err := Validate(v); err == nil {
+			t.Errorf("Validate(%v) = nil, want an error", v)
+		}
+	}
+}
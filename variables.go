@@ -1,3 +0,0 @@
-package main
-
-var name = "Nic"
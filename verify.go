@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nicolasjhampton/hellogo/lessons"
+)
+
+// verifyCommand runs lessons with their output captured and compares it
+// against the golden files checked in under golden/. This is how we
+// find out a Go upgrade or an edit changed what a lesson prints.
+func verifyCommand(args []string) error {
+	fs := newFlagSet("verify", "[lessons...]")
+	f := filterFlags(fs)
+	opts := runnerFlags(fs)
+	dir := fs.String("dir", "golden", "directory holding the golden files")
+	update := fs.Bool("update", false, "rewrite the golden files with the current output")
+	ls, err := selectLessons(fs, f, args)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, l := range ls {
+		var out bytes.Buffer
+		var res lessons.Result
+		err := lessons.Capture(&out, func() {
+			res = lessons.RunLesson(l, *opts)
+		})
+		if err != nil {
+			return fmt.Errorf("capturing %s: %w", l.ID, err)
+		}
+		got := lessons.Normalize(l, out.String())
+		path := goldenPath(*dir, l)
+
+		// The output can't be trusted if the lesson didn't run the way
+		// it's supposed to, so there's no point diffing it, and saving
+		// it would make a half finished run the new truth
+		if res.Status != lessons.Passed {
+			failed++
+			lessons.Report(os.Stdout, res)
+			if *update {
+				fmt.Printf("skipped %s: not updating %s\n", l.ID, path)
+			}
+			continue
+		}
+
+		if *update {
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+				return err
+			}
+			fmt.Printf("updated %s\n", path)
+			continue
+		}
+
+		want, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			failed++
+			fmt.Printf("FAIL %s: no golden file %s (run verify --update to create it)\n", l.ID, path)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if diff := lessons.Diff(string(want), got); diff != "" {
+			failed++
+			fmt.Printf("FAIL %s: output doesn't match %s\n", l.ID, path)
+			fmt.Print(diff)
+			continue
+		}
+		fmt.Printf("ok   %s\n", l.ID)
+	}
+
+	if failed > 0 && *update {
+		return fmt.Errorf("%d of %d lessons didn't pass, so their golden files weren't updated", failed, len(ls))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d lessons didn't match their golden files", failed, len(ls))
+	}
+	return nil
+}
+
+// goldenPath is where a lesson's golden file lives, like
+// golden/channels/select.golden
+func goldenPath(dir string, l lessons.Lesson) string {
+	return filepath.Join(dir, filepath.FromSlash(l.ID)+".golden")
+}